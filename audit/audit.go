@@ -0,0 +1,175 @@
+package audit
+
+import (
+	// Native packages
+	"encoding/json"
+	"reflect"
+
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/models"
+)
+
+const ACTION_CREATE = "create"
+const ACTION_UPDATE = "update"
+const ACTION_DESTROY = "destroy"
+const ACTION_RESTORE = "restore"
+const ACTION_ROLLBACK = "rollback"
+const ACTION_PURGE = "purge"
+const ACTION_TRANSITION = "transition"
+const ACTION_ROLE = "role"
+const ACTION_SUSPEND = "suspend"
+const ACTION_UNSUSPEND = "unsuspend"
+
+const RESOURCE_USER = "user"
+const RESOURCE_CATEGORY = "category"
+const RESOURCE_STATEMENT = "statement"
+
+// @NOTE Translations are recorded by statement UUID, see controllers.StatementTranslationsController.
+const RESOURCE_TRANSLATION = "translation"
+
+// @NOTE Marker stored instead of personal data, entries cannot be purged along with the resource.
+const REDACTED = "[redacted]"
+
+// @NOTE Personal data per resource type, entries only show whether these were set, changed or cleared.
+var redactedProperties = map[string][]string{
+	RESOURCE_USER: {"email", "firstName", "lastName"},
+}
+
+/**
+ *	Replaces values of personal data properties in changes with REDACTED, missing values stay null.
+ *
+ *	@param resourceType string - One of RESOURCE_* constants.
+ *	@param changes models.AuditChanges - Changes to redact in place.
+ *
+ *	@return void
+ */
+func redact(resourceType string, changes models.AuditChanges) {
+	for _, property := range redactedProperties[resourceType] {
+		change, hasChange := changes[property]
+
+		if !hasChange {
+			continue
+		}
+
+		if change.From != nil {
+			change.From = REDACTED
+		}
+
+		if change.To != nil {
+			change.To = REDACTED
+		}
+
+		changes[property] = change
+	}
+}
+
+/**
+ *	Returns changed properties between two resource states, keyed by JSON property name.
+ *	@NOTE Properties hidden from JSON (passwords, keys) never end up in the audit log.
+ *
+ *	@param before interface{} - Resource before change, nil if created.
+ *	@param after interface{} - Resource after change, nil if destroyed.
+ *
+ *	@return models.AuditChanges, error
+ */
+func Diff(before interface{}, after interface{}) (models.AuditChanges, error) {
+	changes := models.AuditChanges{}
+
+	beforeProperties, beforeError := toProperties(before)
+
+	if beforeError != nil {
+		return changes, beforeError
+	}
+
+	afterProperties, afterError := toProperties(after)
+
+	if afterError != nil {
+		return changes, afterError
+	}
+
+	// @NOTE Missing properties count as null, so creating or destroying only lists properties with a value.
+	for property, beforeValue := range beforeProperties {
+		if !reflect.DeepEqual(beforeValue, afterProperties[property]) {
+			changes[property] = models.AuditChange{From: beforeValue, To: afterProperties[property]}
+		}
+	}
+
+	for property, afterValue := range afterProperties {
+		if _, hasBefore := beforeProperties[property]; !hasBefore && afterValue != nil {
+			changes[property] = models.AuditChange{From: nil, To: afterValue}
+		}
+	}
+
+	return changes, nil
+}
+
+/**
+ *	Appends an audit entry, pass the transaction of the change so that both are committed or rolled back together.
+ *	@NOTE Personal data is redacted, see redactedProperties.
+ *
+ *	@param tx *gorm.DB - Transaction of the change.
+ *	@param actor models.User - Acting user, ID is 0 for unauthenticated requests.
+ *	@param action string - One of ACTION_* constants.
+ *	@param resourceType string - One of RESOURCE_* constants.
+ *	@param resourceUuid string - Resource UUID.
+ *	@param before interface{} - Resource before change, nil if created.
+ *	@param after interface{} - Resource after change, nil if destroyed.
+ *
+ *	@return error
+ */
+func Record(tx *gorm.DB, actor models.User, action string, resourceType string, resourceUuid string, before interface{}, after interface{}) error {
+	changes, diffError := Diff(before, after)
+
+	if diffError != nil {
+		return diffError
+	}
+
+	redact(resourceType, changes)
+
+	encodedChanges, encodeError := json.Marshal(changes)
+
+	if encodeError != nil {
+		return encodeError
+	}
+
+	entry := models.AuditEntry{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceUuid: resourceUuid,
+		Changes:      string(encodedChanges),
+		Diff:         changes,
+	}
+
+	if actor.ID != 0 {
+		entry.ActorId = null.IntFrom(int64(actor.ID))
+	}
+
+	return tx.Create(&entry).Error
+}
+
+/**
+ *	Returns resource as generic JSON properties.
+ *
+ *	@param resource interface{} - Resource, nil returns no properties.
+ *
+ *	@return map[string]interface{}, error
+ */
+func toProperties(resource interface{}) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+
+	if resource == nil {
+		return properties, nil
+	}
+
+	encodedResource, encodeError := json.Marshal(resource)
+
+	if encodeError != nil {
+		return properties, encodeError
+	}
+
+	return properties, json.Unmarshal(encodedResource, &properties)
+}
@@ -3,12 +3,27 @@ package constraints
 import (
 	// 3rd party packages
 	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/utils"
 )
 
+const ANY_METHOD = "*"
+
+/**
+ *	Resolved route params, keyed by param name without ":" or "*" prefix.
+ */
+type Params map[string]string
+
+/**
+ *	Guard handler, returns false to deny request.
+ */
+type Guard func(ctx *gin.Context, params Params) bool
+
 type Constraint struct {
 	RequestPath   string
 	RequestMethod string
-	Guard         func(ctx *gin.Context) bool
+	Guard         Guard
 }
 
 type Constraints []Constraint
@@ -16,15 +31,17 @@ type Constraints []Constraint
 var registeredConstraints Constraints
 
 /**
- *	Adds a route constraint using request method, path and a guard handler.
+ *	Adds a route constraint using request method, route pattern and a guard handler.
+ *	Route patterns are matched against router templates, e.g. "/v1/users/:uuid", and may end with a wildcard, e.g. "/v1/users/*".
+ *	@NOTE Constraints are registered from init functions, the constraints package only needs to be imported.
  *
- *	@param requestMethod string
- *	@param requestPath string
- *	@param guardHandler func(ctx *gin.Context) bool
+ *	@param requestMethod string - Request method, or ANY_METHOD.
+ *	@param requestPath string - Route pattern.
+ *	@param guardHandler Guard - Guard handler.
  *
  *	@return void
  */
-func AddConstraint(requestMethod string, requestPath string, guardHandler func(ctx *gin.Context) bool) {
+func AddConstraint(requestMethod string, requestPath string, guardHandler Guard) {
 	registeredConstraints = append(registeredConstraints, Constraint{
 		RequestPath:   requestPath,
 		RequestMethod: requestMethod,
@@ -40,3 +57,70 @@ func AddConstraint(requestMethod string, requestPath string, guardHandler func(c
 func GetConstraints() Constraints {
 	return registeredConstraints
 }
+
+/**
+ *	Returns true if constraint matches request method and template of the route matched by router.
+ *
+ *	@param requestMethod string - Request method.
+ *	@param routeTemplate string - Route template, e.g. "/v1/users/:uuid".
+ *
+ *	@return bool
+ */
+func (constraint Constraint) Match(requestMethod string, routeTemplate string) bool {
+	if constraint.RequestMethod != ANY_METHOD && constraint.RequestMethod != requestMethod {
+		return false
+	}
+
+	return utils.MatchRoute(constraint.RequestPath, routeTemplate)
+}
+
+/**
+ *	Returns a guard that passes only if every guard passes, guards are evaluated in order.
+ *
+ *	@param guards ...Guard - Guards to compose.
+ *
+ *	@return Guard
+ */
+func AllOf(guards ...Guard) Guard {
+	return func(ctx *gin.Context, params Params) bool {
+		for _, guard := range guards {
+			if !guard(ctx, params) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+/**
+ *	Returns a guard that passes if any guard passes, guards are evaluated in order.
+ *
+ *	@param guards ...Guard - Guards to compose.
+ *
+ *	@return Guard
+ */
+func AnyOf(guards ...Guard) Guard {
+	return func(ctx *gin.Context, params Params) bool {
+		for _, guard := range guards {
+			if guard(ctx, params) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+/**
+ *	Returns a guard that negates guard.
+ *
+ *	@param guard Guard - Guard to negate.
+ *
+ *	@return Guard
+ */
+func Not(guard Guard) Guard {
+	return func(ctx *gin.Context, params Params) bool {
+		return !guard(ctx, params)
+	}
+}
@@ -2,36 +2,26 @@ package constraints
 
 import (
 	// 3rd party packages
-	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
 
 	// Local packages
-	"jaha-api/db"
-	"jaha-api/models"
+	"jaha-api/identity"
 )
 
+func init() {
+	// @NOTE Listing users is limited to administrators in every mode, permissions are only enforced in production.
+	AddConstraint("GET", "/v1/users", IsAdmin)
+}
+
 /**
- *	Register function for user specific callbacks.
- *	@NOTE This function *must* be called manually in router.
+ *	Passes if current user is an administrator.
  *
- *	@return void
+ *	@param ctx *gin.Context - Gin context.
+ *	@param params Params - Resolved route params.
+ *
+ *	@return bool
  */
-func UserConstraints() {
-
-	AddConstraint("GET", "/v1/users", func(ctx *gin.Context) bool {
-		var user models.User
-
-		session := sessions.Default(ctx)
-		userId := session.Get("userId")
-
-		if userId != "" {
-			db.GetConnection().First(&user, userId)
-			if user.ID != 0 && user.Role != models.USER_ROLE_ADMIN {
-				return false
-			}
-		}
-
-		return true
-	})
-
+func IsAdmin(ctx *gin.Context, params Params) bool {
+	user := identity.GetUser(ctx)
+	return user.IsAdmin()
 }
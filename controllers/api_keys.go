@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"time"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/utils"
+)
+
+type apiKeysPrototype struct{}
+
+/**
+ *	Lists API keys of a user, revoked keys included.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (apiKeysPrototype) Index(ctx *gin.Context) {
+	var user models.User
+	var apiKeys models.ApiKeys
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	queryError := dbc.Where("`user_id` = ?", user.ID).Order("`created_at` DESC").Find(&apiKeys).Error
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	responders.Json().Success(ctx, apiKeys)
+	return
+}
+
+/**
+ *	Creates a new API key for a user, response is the only time unhashed key is shown.
+ *	@NOTE Key role defaults to, and may not exceed, role of key owner or of the requesting user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (apiKeysPrototype) Create(ctx *gin.Context) {
+	var user models.User
+	var payload models.ApiKeyPayload
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	roleCeiling := user.Role
+
+	if requester := identity.GetUser(ctx); requester.ID != 0 && requester.Role < roleCeiling {
+		roleCeiling = requester.Role
+	}
+
+	if payload.Role == 0 {
+		payload.Role = roleCeiling
+	}
+
+	if payload.Role > roleCeiling {
+		responders.Text().Forbidden(ctx, fmt.Sprintf("Could not create resource, role %d exceeds role of User#%s.", payload.Role, paramId))
+		return
+	}
+
+	rawKey, apiKey := identity.NewApiKey(user, payload.Name, payload.Role)
+
+	if !apiKey.Valid() {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": apiKey.GetErrors(),
+		})
+		return
+	}
+
+	if dbc.Create(&apiKey).Error != nil {
+		responders.Text().ServerError(ctx, "Could not create resource, unknown error.")
+		return
+	}
+
+	responders.Json().Created(ctx, responders.Response{
+		"key":    rawKey,
+		"apiKey": apiKey,
+	})
+	return
+}
+
+/**
+ *	Revokes an API key of a user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (apiKeysPrototype) Destroy(ctx *gin.Context) {
+	var user models.User
+	var apiKey models.ApiKey
+
+	paramId := ctx.Param("uuid")
+	paramKeyId := ctx.Param("key")
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	dbc.Where("`uuid` = ? AND `user_id` = ?", paramKeyId, user.ID).First(&apiKey)
+
+	if apiKey.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("ApiKey#%s not found.", paramKeyId))
+		return
+	}
+
+	if !apiKey.IsActive() {
+		responders.Text().Conflict(ctx, fmt.Sprintf("ApiKey#%s already revoked.", paramKeyId))
+		return
+	}
+
+	if dbc.Model(&apiKey).UpdateColumn("revoked_at", time.Now()).Error != nil {
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not revoke ApiKey#%s.", paramKeyId))
+		return
+	}
+
+	responders.NoContent(ctx)
+	return
+}
+
+func ApiKeysController() apiKeysPrototype {
+	var controllerInstance apiKeysPrototype
+	return controllerInstance
+}
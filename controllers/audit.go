@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	// Native packages
+	"strconv"
+	"time"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/utils"
+)
+
+type auditPrototype struct{}
+
+/**
+ *	Lists audit entries, newest first.
+ *	Filters: "actor" (user UUID), "action", "type", "resource" (resource UUID), "since" and "until" (RFC 3339).
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (auditPrototype) Index(ctx *gin.Context) {
+	var entries models.AuditEntries
+	var collection models.Collection
+	var collectionCount int
+	var queryError error
+
+	params := ctx.Request.URL.Query()
+	paramPage, _ := strconv.Atoi(utils.Pick(params.Get("page"), "1"))
+
+	dbc := db.GetConnection().Model(&models.AuditEntry{})
+
+	if params.Get("actor") != "" {
+		dbc = dbc.Where("`actor_id` IN (SELECT `id` FROM `user` WHERE `uuid` = ?)", params.Get("actor"))
+	}
+
+	if params.Get("action") != "" {
+		dbc = dbc.Where("`action` = ?", params.Get("action"))
+	}
+
+	if params.Get("type") != "" {
+		dbc = dbc.Where("`resource_type` = ?", params.Get("type"))
+	}
+
+	if params.Get("resource") != "" {
+		dbc = dbc.Where("`resource_uuid` = ?", params.Get("resource"))
+	}
+
+	for paramName, operator := range map[string]string{"since": ">=", "until": "<"} {
+		if params.Get(paramName) == "" {
+			continue
+		}
+
+		paramTime, timeError := time.Parse(time.RFC3339, params.Get(paramName))
+
+		if timeError != nil {
+			responders.Text().BadRequest(ctx, "Parameter "+paramName+" must be an RFC 3339 time.")
+			return
+		}
+
+		dbc = dbc.Where("`created_at` "+operator+" ?", paramTime)
+	}
+
+	dbc.Count(&collectionCount)
+
+	collection = models.Collection{}
+	collection.SetLimit(COLLECTION_DEFAULT_LIMIT)
+	collection.Grab(nil, 1, collectionCount)
+	collection.SetPointer(paramPage)
+
+	queryError = dbc.Order("`id` DESC").Limit(collection.Limit).Offset(collection.GetOffset()).Find(&entries).Error
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	collection.Grab(loadAuditActors(entries), paramPage, collectionCount)
+
+	responders.Json().Success(ctx, collection)
+	return
+}
+
+/**
+ *	Decodes audit entries and attaches their actors.
+ *	@NOTE Actors are loaded separately, soft deleted actors are still shown.
+ *
+ *	@param entries models.AuditEntries - Audit entries.
+ *
+ *	@return models.AuditEntries
+ */
+func loadAuditActors(entries models.AuditEntries) models.AuditEntries {
+	var actors models.Users
+
+	actorIds := []int64{}
+
+	for index := range entries {
+		entries[index].Decode()
+
+		if entries[index].ActorId.Valid {
+			actorIds = append(actorIds, entries[index].ActorId.Int64)
+		}
+	}
+
+	if len(actorIds) > 0 {
+		db.GetConnection().Unscoped().Where("`id` IN (?)", actorIds).Find(&actors)
+	}
+
+	for index := range entries {
+		for actorIndex := range actors {
+			if entries[index].ActorId.Valid && int64(actors[actorIndex].ID) == entries[index].ActorId.Int64 {
+				entries[index].Actor = &actors[actorIndex]
+			}
+		}
+	}
+
+	return entries
+}
+
+func AuditController() auditPrototype {
+	var controllerInstance auditPrototype
+	return controllerInstance
+}
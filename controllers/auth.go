@@ -0,0 +1,472 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	// 3rd party packages
+	"github.com/gin-gonic/contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/appleboy/gin-jwt.v2"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/env"
+	"jaha-api/identity"
+	"jaha-api/mail"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/utils"
+)
+
+type authPrototype struct{}
+
+/**
+ *	Issues an access token and a refresh token, sends server error response and returns false on failure.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param user models.User - Token owner.
+ *
+ *	@return bool
+ */
+func respondWithTokens(ctx *gin.Context, user models.User) bool {
+	accessToken, accessExpiresAt, signError := identity.CreateAccessToken(user)
+
+	if signError != nil {
+		responders.Text().ServerError(ctx, "Could not create access token.")
+		return false
+	}
+
+	refreshToken, storedRefreshToken, createError := identity.CreateRefreshToken(db.GetConnection(), user)
+
+	if createError != nil {
+		responders.Text().ServerError(ctx, "Could not create refresh token.")
+		return false
+	}
+
+	responders.Json().Success(ctx, responders.Response{
+		"token":         accessToken,
+		"expire":        accessExpiresAt.Format(time.RFC3339),
+		"refreshToken":  refreshToken,
+		"refreshExpire": storedRefreshToken.ExpiresAt.Format(time.RFC3339),
+	})
+
+	return true
+}
+
+/**
+ *	Authenticates user by email and password, responds with access and refresh tokens.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (authPrototype) Login(ctx *gin.Context) {
+	var payload models.LoginPayload
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	dbc := db.GetConnection()
+	clientIp := identity.GetClientIp(ctx)
+
+	// @NOTE Throttle before verifying password, so locked out attempts never reach bcrypt.
+	if status, retryAfter := identity.CheckLogin(dbc, payload.Username, clientIp); status != 0 {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+		if status == http.StatusLocked {
+			responders.Text().Locked(ctx, "Too many failed login attempts, login is temporarily locked.")
+		} else {
+			responders.Text().TooManyRequests(ctx, "Too many failed login attempts, try again later.")
+		}
+		return
+	}
+
+	user, authenticated := identity.Authenticate(payload.Username, payload.Password)
+
+	if !authenticated {
+		if recordError := identity.RecordLoginFailure(dbc, payload.Username, clientIp); recordError != nil {
+			log.Printf("Could not record failed login attempt: %s", recordError)
+		}
+
+		responders.Text().Unauthorized(ctx, "Incorrect Username / Password")
+		return
+	}
+
+	if recordError := identity.RecordLoginSuccess(dbc, payload.Username); recordError != nil {
+		log.Printf("Could not clear failed login attempts: %s", recordError)
+	}
+
+	if user.IsSuspended() {
+		responders.Text().Forbidden(ctx, "User account is suspended.")
+		return
+	}
+
+	respondWithTokens(ctx, user)
+	return
+}
+
+/**
+ *	Exchanges a refresh token for new access and refresh tokens, the used refresh token is revoked.
+ *	@NOTE Reusing a revoked refresh token revokes every refresh token of its user, since the token has likely leaked.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (authPrototype) Token(ctx *gin.Context) {
+	var payload models.RefreshTokenPayload
+	var user models.User
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	dbc := db.GetConnection()
+	refreshToken := identity.FindRefreshToken(dbc, payload.RefreshToken)
+
+	if refreshToken.ID == 0 {
+		responders.Text().Unauthorized(ctx, "Invalid refresh token.")
+		return
+	}
+
+	if refreshToken.RevokedAt.Valid {
+		identity.RevokeRefreshTokens(dbc, refreshToken.UserId)
+		responders.Text().Unauthorized(ctx, "Refresh token is revoked.")
+		return
+	}
+
+	if !refreshToken.IsActive() {
+		responders.Text().Unauthorized(ctx, "Refresh token is expired.")
+		return
+	}
+
+	dbc.First(&user, refreshToken.UserId)
+
+	if !user.CanAuthenticate() {
+		responders.Text().Unauthorized(ctx, "Invalid refresh token.")
+		return
+	}
+
+	revokeError := identity.RevokeRefreshToken(dbc, &refreshToken)
+
+	// @NOTE Token was used by a concurrent request, treated as reuse like a revoked token.
+	if revokeError == identity.ErrRefreshTokenRevoked {
+		identity.RevokeRefreshTokens(dbc, refreshToken.UserId)
+		responders.Text().Unauthorized(ctx, "Refresh token is revoked.")
+		return
+	}
+
+	if revokeError != nil {
+		responders.Text().ServerError(ctx, "Could not revoke refresh token.")
+		return
+	}
+
+	respondWithTokens(ctx, user)
+	return
+}
+
+/**
+ *	Revokes current access token and, if given, a refresh token. Setting "all" rotates user auth key, ending every session.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (authPrototype) Logout(ctx *gin.Context) {
+	var payload models.LogoutPayload
+	var revokeError error
+
+	user := identity.GetUser(ctx)
+
+	if user.ID == 0 {
+		responders.Text().Unauthorized(ctx, "Logout requires an authenticated user.")
+		return
+	}
+
+	// @NOTE Payload is optional, an empty body only revokes the current access token.
+	ctx.BindJSON(&payload)
+
+	dbc := db.GetConnection()
+
+	if payload.All {
+		revokeError = identity.RotateAuthKey(dbc, &user)
+	} else {
+		revokeError = identity.RevokeAccessToken(dbc, user, jwt.ExtractClaims(ctx))
+
+		if revokeError == nil && payload.RefreshToken != "" {
+			refreshToken := identity.FindRefreshToken(dbc, payload.RefreshToken)
+
+			if refreshToken.ID != 0 && refreshToken.UserId == user.ID && !refreshToken.RevokedAt.Valid {
+				revokeError = identity.RevokeRefreshToken(dbc, &refreshToken)
+			}
+
+			// @NOTE Token revoked meanwhile is logged out all the same.
+			if revokeError == identity.ErrRefreshTokenRevoked {
+				revokeError = nil
+			}
+		}
+	}
+
+	if revokeError != nil {
+		responders.Text().ServerError(ctx, "Could not revoke tokens.")
+		return
+	}
+
+	// @NOTE Identity is only resolved from credentials, session user is cleared for sessions of earlier releases.
+	session := sessions.Default(ctx)
+	session.Delete("userId")
+	session.Save()
+
+	responders.Text().Success(ctx, "Logged out.")
+	return
+}
+
+/**
+ *	Creates a single-use token and mails it to user.
+ *
+ *	@param user models.User - Recipient.
+ *	@param purpose string - Token purpose, USER_TOKEN_PURPOSE_RESET or USER_TOKEN_PURPOSE_VERIFY.
+ *
+ *	@return error
+ */
+func sendUserToken(user models.User, purpose string) error {
+	var message mail.Message
+
+	rawToken, createError := identity.CreateUserToken(db.GetConnection(), user, purpose)
+
+	if createError != nil {
+		return createError
+	}
+
+	if purpose == models.USER_TOKEN_PURPOSE_RESET {
+		message = mail.Message{
+			To:      user.Email,
+			Subject: "Reset your password",
+			Body: fmt.Sprintf(
+				"Hi %s,\r\n\r\nSomeone asked to reset your password. Follow the link below within %d minutes to choose a new one:\r\n\r\n%s/reset?token=%s\r\n\r\nIf it was not you, ignore this message.",
+				user.FirstName, int(identity.RESET_TOKEN_TIMEOUT.Minutes()), env.GetAppUrl(), rawToken,
+			),
+		}
+	} else {
+		message = mail.Message{
+			To:      user.Email,
+			Subject: "Verify your email address",
+			Body: fmt.Sprintf(
+				"Hi %s,\r\n\r\nFollow the link below within %d hours to verify your email address:\r\n\r\n%s/verify?token=%s",
+				user.FirstName, int(identity.VERIFY_TOKEN_TIMEOUT.Hours()), env.GetAppUrl(), rawToken,
+			),
+		}
+	}
+
+	return mail.Send(message)
+}
+
+/**
+ *	Sends password reset mail.
+ *	@NOTE Responds with success even if email is unknown, so that registered addresses cannot be discovered.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (authPrototype) Forgot(ctx *gin.Context) {
+	var payload models.ForgotPasswordPayload
+	var user models.User
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	db.GetConnection().Where("`email` = ?", payload.Email).First(&user)
+
+	// @NOTE Failures are only logged, an error response would tell that the address is registered.
+	if user.ID != 0 {
+		if sendError := sendUserToken(user, models.USER_TOKEN_PURPOSE_RESET); sendError != nil {
+			log.Printf("Could not send password reset mail to User#%s: %s", user.UUID, sendError)
+		}
+	}
+
+	responders.Text().Success(ctx, "If the address belongs to a user, password reset instructions have been sent.")
+	return
+}
+
+/**
+ *	Sets new password using a password reset token, every session of user is ended.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (authPrototype) Reset(ctx *gin.Context) {
+	var payload models.ResetPasswordPayload
+	var user models.User
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+	userToken := identity.FindUserToken(tx, payload.Token, models.USER_TOKEN_PURPOSE_RESET)
+
+	if userToken.ID == 0 || !userToken.IsActive() {
+		tx.Rollback()
+		responders.Text().BadRequest(ctx, "Password reset token is invalid or expired.")
+		return
+	}
+
+	tx.First(&user, userToken.UserId)
+
+	if user.ID == 0 {
+		tx.Rollback()
+		responders.Text().BadRequest(ctx, "Password reset token is invalid or expired.")
+		return
+	}
+
+	resetError := identity.UseUserToken(tx, &userToken)
+
+	if resetError == nil {
+		resetError = tx.Model(&user).UpdateColumn("password", utils.PasswordCreate(payload.Password)).Error
+	}
+
+	if resetError == nil {
+		resetError = identity.RotateAuthKey(tx, &user)
+	}
+
+	if resetError == identity.ErrUserTokenUsed {
+		tx.Rollback()
+		responders.Text().BadRequest(ctx, "Password reset token is invalid or expired.")
+		return
+	}
+
+	if resetError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, "Could not reset password.")
+		return
+	}
+
+	tx.Commit()
+
+	responders.Text().Success(ctx, "Password has been reset.")
+	return
+}
+
+/**
+ *	Verifies user email address using a verification token.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (authPrototype) Verify(ctx *gin.Context) {
+	var payload models.VerifyEmailPayload
+	var user models.User
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+	userToken := identity.FindUserToken(tx, payload.Token, models.USER_TOKEN_PURPOSE_VERIFY)
+
+	if userToken.ID != 0 && userToken.IsActive() {
+		tx.First(&user, userToken.UserId)
+	}
+
+	if user.ID == 0 || identity.UseUserToken(tx, &userToken) != nil {
+		tx.Rollback()
+		responders.Text().BadRequest(ctx, "Verification token is invalid or expired.")
+		return
+	}
+
+	if tx.Model(&user).UpdateColumn("verified_at", time.Now()).Error != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, "Could not verify email address.")
+		return
+	}
+
+	tx.Commit()
+
+	responders.Text().Success(ctx, "Email address has been verified.")
+	return
+}
+
+/**
+ *	Sends a new verification mail to authenticated user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (authPrototype) SendVerification(ctx *gin.Context) {
+	user := identity.GetUser(ctx)
+
+	if user.ID == 0 {
+		responders.Text().Unauthorized(ctx, "Verification requires an authenticated user.")
+		return
+	}
+
+	if user.IsVerified() {
+		responders.Text().Conflict(ctx, fmt.Sprintf("User#%s is already verified.", user.UUID))
+		return
+	}
+
+	if sendError := sendUserToken(user, models.USER_TOKEN_PURPOSE_VERIFY); sendError != nil {
+		responders.Text().ServerError(ctx, "Could not send verification mail.")
+		return
+	}
+
+	responders.Text().Success(ctx, "Verification mail has been sent.")
+	return
+}
+
+func AuthController() authPrototype {
+	var controllerInstance authPrototype
+	return controllerInstance
+}
@@ -4,6 +4,7 @@ import (
 	// Native packages
 	"fmt"
 	"strconv"
+	"time"
 
 	// 3rd party packages
 	"github.com/gin-gonic/gin"
@@ -11,16 +12,20 @@ import (
 	"gopkg.in/guregu/null.v3"
 
 	// Local packages
+	"jaha-api/audit"
 	"jaha-api/db"
+	"jaha-api/identity"
 	"jaha-api/models"
 	"jaha-api/responders"
+	"jaha-api/revisions"
+	"jaha-api/scopes"
 	"jaha-api/utils"
 )
 
 type categoriesPrototype struct{}
 
 /**
- *	Lists published resources.
+ *	Lists published resources, moderators may list deleted resources with "trashed" param, see filterTrashed.
  *
  *	@param ctx gin.Context - Gin context pointer.
  *
@@ -36,7 +41,11 @@ func (categoriesPrototype) Index(ctx *gin.Context) {
 	paramPage, _ := strconv.Atoi(utils.Pick(params.Get("page"), "1"))
 	paramOrderBy := utils.Pick(params.Get("orderBy"), "createdAt:asc")
 
-	dbc := db.GetConnection()
+	dbc, _, trashedValid := filterTrashed(ctx, db.GetConnection(), scopes.Category().Deleted)
+
+	if !trashedValid {
+		return
+	}
 
 	dbc.Model(&models.Category{}).Count(&collectionCount)
 
@@ -114,6 +123,7 @@ func (categoriesPrototype) Create(ctx *gin.Context) {
 		return
 	}
 
+	// @NOTE Default level is required, an unrated category must never pass as family friendly.
 	mergo.Merge(&category, models.Category{
 		UUID: utils.RandomString(8),
 	})
@@ -126,13 +136,25 @@ func (categoriesPrototype) Create(ctx *gin.Context) {
 		return
 	}
 
-	createError = dbc.Create(&category).Error
+	tx := dbc.Begin()
+	createError = tx.Create(&category).Error
+
+	if createError == nil {
+		createError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_CREATE, audit.RESOURCE_CATEGORY, category.UUID, nil, category)
+	}
+
+	if createError == nil {
+		_, createError = revisions.Record(tx, identity.GetUser(ctx), audit.RESOURCE_CATEGORY, category.ID, nil, category.Snapshot(), 0)
+	}
 
 	if createError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, "Could not create resource, unknown error.")
 		return
 	}
 
+	tx.Commit()
+
 	responders.Json().Success(ctx, category)
 	return
 }
@@ -181,19 +203,35 @@ func (categoriesPrototype) Update(ctx *gin.Context) {
 		return
 	}
 
-	updateError := dbc.Model(&category).Unscoped().Updates(payload).Error
+	before := category
+
+	tx := dbc.Begin()
+	updateError := tx.Model(&category).Unscoped().Updates(payload).Error
+
+	if updateError == nil {
+		updateError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_UPDATE, audit.RESOURCE_CATEGORY, category.UUID, before, category)
+	}
+
+	if updateError == nil {
+		_, updateError = revisions.Record(tx, identity.GetUser(ctx), audit.RESOURCE_CATEGORY, category.ID, before.Snapshot(), category.Snapshot(), 0)
+	}
 
 	if updateError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, fmt.Sprintf("Could not update Category#%s.", paramId))
 		return
 	}
 
+	tx.Commit()
+
 	responders.Json().Success(ctx, category)
 	return
 }
 
 /**
- *	Destroys existing resource.
+ *	Destroys existing resource, "cascade" param sets what happens to its live statements:
+ *	"block" (default) refuses while category has statements, "delete" destroys them along with category
+ *	and "reassign" moves them to category set by "reassignTo" param.
  *
  *	@param ctx gin.Context - Gin context pointer.
  *
@@ -201,10 +239,19 @@ func (categoriesPrototype) Update(ctx *gin.Context) {
  */
 func (categoriesPrototype) Destroy(ctx *gin.Context) {
 	var category models.Category
+	var reassignCategory models.Category
+	var statements models.Statements
 	var queryError error
 	var destroyError error
 
 	paramId := ctx.Param("uuid")
+	paramCascade := utils.Pick(ctx.Query("cascade"), models.CATEGORY_CASCADE_BLOCK)
+	paramReassignTo := ctx.Query("reassignTo")
+
+	if paramCascade != models.CATEGORY_CASCADE_BLOCK && paramCascade != models.CATEGORY_CASCADE_DELETE && paramCascade != models.CATEGORY_CASCADE_REASSIGN {
+		responders.Text().BadRequest(ctx, "Parameter cascade must be one of block, delete or reassign.")
+		return
+	}
 
 	dbc := db.GetConnection()
 	queryError = dbc.Unscoped().Where("`uuid` = ?", paramId).First(&category).Error
@@ -219,19 +266,112 @@ func (categoriesPrototype) Destroy(ctx *gin.Context) {
 		return
 	}
 
-	destroyError = dbc.Delete(&category).Error
+	if category.DeletedAt.Valid {
+		responders.Text().Conflict(ctx, fmt.Sprintf("Category#%s already destroyed.", paramId))
+		return
+	}
+
+	if paramCascade == models.CATEGORY_CASCADE_REASSIGN {
+		if paramReassignTo == "" || paramReassignTo == paramId {
+			responders.Text().BadRequest(ctx, "Parameter reassignTo must be the UUID of another category.")
+			return
+		}
+	}
+
+	user := identity.GetUser(ctx)
+
+	tx := dbc.Begin()
+
+	// @NOTE Category and its statements are locked, statements created meanwhile would be left without audit entries.
+	queryError = tx.Set("gorm:query_option", "FOR UPDATE").Unscoped().Where("`id` = ?", category.ID).First(&models.Category{}).Error
+
+	if queryError == nil {
+		queryError = tx.Set("gorm:query_option", "FOR UPDATE").Where("`category_id` = ?", category.ID).Find(&statements).Error
+	}
+
+	if queryError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	// @NOTE Reassign target is locked as well, so that it cannot be destroyed before statements are moved.
+	if paramCascade == models.CATEGORY_CASCADE_REASSIGN {
+		tx.Set("gorm:query_option", "FOR UPDATE").Where("`uuid` = ?", paramReassignTo).First(&reassignCategory)
+
+		if reassignCategory.ID == 0 {
+			tx.Rollback()
+			responders.Text().NotFound(ctx, fmt.Sprintf("Category#%s not found.", paramReassignTo))
+			return
+		}
+	}
+
+	if paramCascade == models.CATEGORY_CASCADE_BLOCK && len(statements) > 0 {
+		tx.Rollback()
+		responders.Text().Conflict(ctx, fmt.Sprintf("Category#%s has %d statement(s), destroy with cascade set to delete or reassign.", paramId, len(statements)))
+		return
+	}
+
+	statementIds := []int{}
+
+	for index := range statements {
+		statements[index].Category = category
+		statementIds = append(statementIds, statements[index].ID)
+	}
+
+	destroyError = tx.Delete(&category).Error
+
+	if destroyError == nil && len(statements) > 0 {
+		statementQuery := tx.Model(&models.Statement{}).Where("`id` IN (?)", statementIds)
+
+		// @NOTE Cascaded statements are flagged so that Restore brings back only those.
+		if paramCascade == models.CATEGORY_CASCADE_DELETE {
+			destroyError = statementQuery.Updates(map[string]interface{}{"deleted_at": time.Now(), "deleted_with_category": true}).Error
+		} else {
+			destroyError = statementQuery.Update("category_id", reassignCategory.ID).Error
+		}
+	}
+
+	for _, statement := range statements {
+		if destroyError != nil {
+			break
+		}
+
+		if paramCascade == models.CATEGORY_CASCADE_DELETE {
+			destroyError = audit.Record(tx, user, audit.ACTION_DESTROY, audit.RESOURCE_STATEMENT, statement.UUID, statement, nil)
+			continue
+		}
+
+		after := statement
+		after.Category = reassignCategory
+		after.CategoryId = reassignCategory.ID
+
+		destroyError = audit.Record(tx, user, audit.ACTION_UPDATE, audit.RESOURCE_STATEMENT, statement.UUID, statement, after)
+
+		if destroyError == nil {
+			_, destroyError = revisions.Record(tx, user, audit.RESOURCE_STATEMENT, statement.ID, statement.Snapshot(), after.Snapshot(), 0)
+		}
+	}
+
+	if destroyError == nil {
+		destroyError = audit.Record(tx, user, audit.ACTION_DESTROY, audit.RESOURCE_CATEGORY, category.UUID, category, nil)
+	}
 
 	if destroyError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, fmt.Sprintf("Could not destroy resource Category#%s.", paramId))
 		return
 	}
 
+	tx.Commit()
+
 	responders.NoContent(ctx)
 	return
 }
 
 /**
- *	Restores soft deleted resource.
+ *	Restores soft deleted resource along with statements destroyed by its cascade.
+ *	@NOTE Statements destroyed on their own stay destroyed.
  *
  *	@param ctx gin.Context - Gin context pointer.
  *
@@ -239,6 +379,7 @@ func (categoriesPrototype) Destroy(ctx *gin.Context) {
  */
 func (categoriesPrototype) Restore(ctx *gin.Context) {
 	var category models.Category
+	var statements models.Statements
 	var queryError error
 	var restoreError error
 
@@ -262,13 +403,48 @@ func (categoriesPrototype) Restore(ctx *gin.Context) {
 		return
 	}
 
-	restoreError = dbc.Model(&category).Unscoped().Update("deleted_at", "NULL").Error
+	queryError = dbc.Unscoped().Where("`category_id` = ? AND `deleted_with_category` = ?", category.ID, true).Find(&statements).Error
 
-	if restoreError != nil {
+	if queryError != nil {
 		responders.Text().ServerError(ctx, queryError.Error())
 		return
 	}
 
+	user := identity.GetUser(ctx)
+	before := category
+
+	tx := dbc.Begin()
+	restoreError = tx.Model(&category).Unscoped().Update("deleted_at", nil).Error
+
+	if restoreError == nil && len(statements) > 0 {
+		restoreError = tx.Model(&models.Statement{}).Unscoped().Where("`category_id` = ? AND `deleted_with_category` = ?", category.ID, true).
+			Updates(map[string]interface{}{"deleted_at": nil, "deleted_with_category": false}).Error
+	}
+
+	for _, statement := range statements {
+		if restoreError != nil {
+			break
+		}
+
+		after := statement
+		after.DeletedAt = null.Time{}
+		after.DeletedWithCategory = false
+
+		restoreError = audit.Record(tx, user, audit.ACTION_RESTORE, audit.RESOURCE_STATEMENT, statement.UUID, statement, after)
+	}
+
+	if restoreError == nil {
+		restoreError = audit.Record(tx, user, audit.ACTION_RESTORE, audit.RESOURCE_CATEGORY, category.UUID, before, category)
+	}
+
+	if restoreError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not restore resource Category#%s.", paramId))
+		return
+	}
+
+	tx.Commit()
+
 	responders.Json().Success(ctx, category)
 	return
 }
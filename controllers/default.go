@@ -3,13 +3,18 @@ package controllers
 import (
 	// 3rd party packages
 	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
 
 	// Local packages
+	"jaha-api/identity"
 	"jaha-api/responders"
 )
 
 const COLLECTION_DEFAULT_LIMIT = 25
 
+const TRASHED_ONLY = "only"
+const TRASHED_WITH = "with"
+
 type defaultPrototype struct{}
 
 /**
@@ -23,6 +28,46 @@ func (defaultPrototype) MissingRoute(ctx *gin.Context) {
 	responders.Text().NotFound(ctx, "Route not found.")
 }
 
+/**
+ *	Applies "trashed" param to a listing query, "only" lists soft deleted resources and "with" lists them along with the rest.
+ *	"scope=deleted" is the same as "trashed=only". Responds and returns false if param is invalid or user is not a moderator.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param dbc *gorm.DB - Listing query.
+ *	@param deletedScope func(*gorm.DB) *gorm.DB - Resource scope matching soft deleted rows, e.g. scopes.Statement().Deleted.
+ *
+ *	@return *gorm.DB, string, bool
+ */
+func filterTrashed(ctx *gin.Context, dbc *gorm.DB, deletedScope func(*gorm.DB) *gorm.DB) (*gorm.DB, string, bool) {
+	paramTrashed := ctx.Query("trashed")
+
+	if ctx.Query("scope") == "deleted" {
+		paramTrashed = TRASHED_ONLY
+	}
+
+	if paramTrashed == "" {
+		return dbc, paramTrashed, true
+	}
+
+	if paramTrashed != TRASHED_ONLY && paramTrashed != TRASHED_WITH {
+		responders.Text().BadRequest(ctx, "Parameter trashed must be one of only or with.")
+		return dbc, paramTrashed, false
+	}
+
+	if user := identity.GetUser(ctx); !user.IsModerator() {
+		responders.Text().Forbidden(ctx, "Only moderators can list deleted resources.")
+		return dbc, paramTrashed, false
+	}
+
+	dbc = dbc.Unscoped()
+
+	if paramTrashed == TRASHED_ONLY {
+		dbc = dbc.Scopes(deletedScope)
+	}
+
+	return dbc, paramTrashed, true
+}
+
 /**
  *	Returns instanciated "controller".
  *	@NOTE Classes aren't present in Go, return a struct with field methods instead.
@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"strings"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/scopes"
+	"jaha-api/utils"
+)
+
+type gamesPrototype struct{}
+
+/**
+ *	Counts drawn and remaining statements and sets them on game.
+ *
+ *	@param dbc *gorm.DB - Connection or transaction to count with.
+ *	@param game *models.Game - Game with preloaded categories.
+ *
+ *	@return error
+ */
+func countGameDeck(dbc *gorm.DB, game *models.Game) error {
+	var drawnCount int
+	var remainingCount int
+	var countError error
+
+	countError = dbc.Model(&models.GameStatement{}).Where("`game_id` = ?", game.ID).Count(&drawnCount).Error
+
+	if countError != nil {
+		return countError
+	}
+
+	countError = dbc.Model(&models.Statement{}).Scopes(scopes.Game().Deck(*game), scopes.Game().Undrawn(*game)).Count(&remainingCount).Error
+
+	if countError != nil {
+		return countError
+	}
+
+	game.SetDeckCounts(drawnCount, remainingCount)
+
+	return nil
+}
+
+/**
+ *	Draws a random statement from game deck and records it as drawn, statement ID is 0 when deck is exhausted.
+ *	@NOTE Game row should be locked by the calling transaction.
+ *
+ *	@param tx *gorm.DB - Transaction to draw in.
+ *	@param game *models.Game - Game with preloaded categories.
+ *
+ *	@return models.Statement, error
+ */
+func drawGameStatement(tx *gorm.DB, game *models.Game) (models.Statement, error) {
+	var statement models.Statement
+	var drawError error
+
+	drawError = tx.Preload("Category").Scopes(scopes.Game().Deck(*game), scopes.Game().Undrawn(*game)).Order("RAND()").First(&statement).Error
+
+	// @NOTE Only a missing row means the deck is exhausted, other errors must not be reported as such.
+	if drawError == gorm.ErrRecordNotFound {
+		return statement, countGameDeck(tx, game)
+	}
+
+	if drawError != nil {
+		return models.Statement{}, drawError
+	}
+
+	drawError = tx.Create(&models.GameStatement{
+		GameId:      game.ID,
+		StatementId: statement.ID,
+	}).Error
+
+	if drawError != nil {
+		return statement, drawError
+	}
+
+	return statement, countGameDeck(tx, game)
+}
+
+/**
+ *	Retrieves published resource.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (gamesPrototype) Show(ctx *gin.Context) {
+	var game models.Game
+	var queryError error
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	queryError = dbc.Preload("Categories").Where("`uuid` = ?", paramId).First(&game).Error
+
+	if game.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Game#%s not found.", paramId))
+		return
+	}
+
+	if queryError == nil {
+		queryError = countGameDeck(dbc, &game)
+	}
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	responders.Json().Success(ctx, game)
+	return
+}
+
+/**
+ *	Creates a new resource, deck is built from statements in selected categories.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (gamesPrototype) Create(ctx *gin.Context) {
+	var payload models.GamePayload
+	var game models.Game
+	var categories models.Categories
+	var missingCategories []string
+	var createError error
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` IN (?)", payload.Categories).Find(&categories)
+
+	for _, categoryId := range payload.Categories {
+		categoryFound := false
+
+		for _, category := range categories {
+			if category.UUID == categoryId {
+				categoryFound = true
+				break
+			}
+		}
+
+		if !categoryFound {
+			missingCategories = append(missingCategories, categoryId)
+		}
+	}
+
+	if len(missingCategories) > 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Category#%s not found.", strings.Join(missingCategories, ", Category#")))
+		return
+	}
+
+	game = models.Game{
+		UUID:       utils.RandomString(8),
+		Categories: categories,
+	}
+
+	// @NOTE Deck level defaults to the level of the requesting client, see requestMaxLevel.
+	if payload.MaxLevel != 0 {
+		game.MaxLevel = null.IntFrom(int64(payload.MaxLevel))
+	} else if maxLevel := requestMaxLevel(ctx); maxLevel != 0 {
+		game.MaxLevel = null.IntFrom(int64(maxLevel))
+	}
+
+	if !game.Valid() {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": game.GetErrors(),
+		})
+		return
+	}
+
+	tx := dbc.Begin()
+
+	// @NOTE Categories are only linked, never saved through the game.
+	createError = tx.Set("gorm:save_associations", false).Create(&game).Error
+
+	for _, category := range categories {
+		if createError != nil {
+			break
+		}
+
+		createError = tx.Exec("INSERT IGNORE INTO `game_category` (`game_id`, `category_id`) VALUES (?, ?)", game.ID, category.ID).Error
+	}
+
+	if createError == nil {
+		createError = countGameDeck(tx, &game)
+	}
+
+	if createError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, "Could not create resource, unknown error.")
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, game)
+	return
+}
+
+/**
+ *	Draws next statement from game deck, a statement is never drawn twice in the same game.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (gamesPrototype) Next(ctx *gin.Context) {
+	var game models.Game
+	var statement models.Statement
+	var queryError error
+	var drawError error
+
+	paramId := ctx.Param("uuid")
+
+	tx := db.GetConnection().Begin()
+
+	// @NOTE Lock game row so concurrent draws cannot hand out the same statement.
+	queryError = tx.Set("gorm:query_option", "FOR UPDATE").Preload("Categories").Where("`uuid` = ?", paramId).First(&game).Error
+
+	if game.ID == 0 {
+		tx.Rollback()
+		responders.Text().NotFound(ctx, fmt.Sprintf("Game#%s not found.", paramId))
+		return
+	}
+
+	if queryError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	statement, drawError = drawGameStatement(tx, &game)
+
+	if drawError == nil && statement.ID == 0 {
+		tx.Rollback()
+		responders.Text().Conflict(ctx, fmt.Sprintf("Game#%s deck is exhausted.", paramId))
+		return
+	}
+
+	if drawError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not draw statement from Game#%s.", paramId))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, responders.Response{
+		"game":      game,
+		"statement": statement,
+	})
+	return
+}
+
+func GamesController() gamesPrototype {
+	var controllerInstance gamesPrototype
+	return controllerInstance
+}
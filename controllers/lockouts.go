@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"strconv"
+	"time"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/utils"
+)
+
+type lockoutsPrototype struct{}
+
+/**
+ *	Lists failed login attempts of emails and client IP addresses, most recent failure first.
+ *	Filters: "type" (email or ip), "locked" (1 for locked out subjects only).
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (lockoutsPrototype) Index(ctx *gin.Context) {
+	var lockouts models.LoginLockouts
+	var collection models.Collection
+	var collectionCount int
+	var queryError error
+
+	params := ctx.Request.URL.Query()
+	paramPage, _ := strconv.Atoi(utils.Pick(params.Get("page"), "1"))
+
+	dbc := db.GetConnection().Model(&models.LoginLockout{})
+
+	if params.Get("type") != "" {
+		dbc = dbc.Where("`subject_type` = ?", params.Get("type"))
+	}
+
+	if params.Get("locked") == "1" {
+		dbc = dbc.Where("`locked_until` > NOW()")
+	}
+
+	dbc.Count(&collectionCount)
+
+	collection = models.Collection{}
+	collection.SetLimit(COLLECTION_DEFAULT_LIMIT)
+	collection.Grab(nil, 1, collectionCount)
+	collection.SetPointer(paramPage)
+
+	queryError = dbc.Order("`last_failure_at` DESC").Limit(collection.Limit).Offset(collection.GetOffset()).Find(&lockouts).Error
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	now := time.Now()
+
+	for index := range lockouts {
+		lockouts[index].Locked = lockouts[index].IsLocked(now)
+	}
+
+	collection.Grab(lockouts, paramPage, collectionCount)
+
+	responders.Json().Success(ctx, collection)
+	return
+}
+
+/**
+ *	Clears failed login attempts and lockout of an email or client IP address.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (lockoutsPrototype) Destroy(ctx *gin.Context) {
+	var lockout models.LoginLockout
+
+	paramType := ctx.Param("type")
+	paramSubject := ctx.Param("subject")
+
+	if paramType == models.LOGIN_SUBJECT_EMAIL {
+		paramSubject = identity.NormalizeLoginEmail(paramSubject)
+	}
+
+	dbc := db.GetConnection()
+	dbc.Where("`subject_type` = ? AND `subject` = ?", paramType, paramSubject).First(&lockout)
+
+	if lockout.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("No failed login attempts of %s %s.", paramType, paramSubject))
+		return
+	}
+
+	if identity.ClearLoginLockoutSubject(dbc, paramType, paramSubject) != nil {
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not clear lockout of %s %s.", paramType, paramSubject))
+		return
+	}
+
+	responders.NoContent(ctx)
+	return
+}
+
+func LockoutsController() lockoutsPrototype {
+	var controllerInstance lockoutsPrototype
+	return controllerInstance
+}
@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"log"
+	"time"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/audit"
+	"jaha-api/db"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/utils"
+)
+
+type mePrototype struct{}
+
+type meVoteExport struct {
+	Statement string    `json:"statement"`
+	Value     int       `json:"value"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+/**
+ *	Returns authenticated user, sends unauthorized response and returns false if there is none.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param user *models.User - User to load.
+ *
+ *	@return bool
+ */
+func findMe(ctx *gin.Context, user *models.User) bool {
+	*user = identity.GetUser(ctx)
+
+	if user.ID == 0 {
+		responders.Text().Unauthorized(ctx, "Resource requires an authenticated user.")
+		return false
+	}
+
+	return true
+}
+
+/**
+ *	Retrieves authenticated user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (mePrototype) Show(ctx *gin.Context) {
+	var user models.User
+
+	if !findMe(ctx, &user) {
+		return
+	}
+
+	responders.Json().Success(ctx, user)
+	return
+}
+
+/**
+ *	Updates authenticated user, changing email requires verifying the new address.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (mePrototype) Update(ctx *gin.Context) {
+	var user models.User
+	var existing models.User
+	var payload models.MePayload
+
+	if !findMe(ctx, &user) {
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	if payload == (models.MePayload{}) {
+		responders.Text().BadRequest(ctx, "Payload cannot be empty or malformed.")
+		return
+	}
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	dbc := db.GetConnection()
+	emailChanged := payload.Email != "" && payload.Email != user.Email
+
+	if emailChanged {
+		dbc.Unscoped().Where("`email` = ?", payload.Email).First(&existing)
+
+		if existing.ID != 0 {
+			responders.Text().Conflict(ctx, "Could not update resource, email is already in use.")
+			return
+		}
+	}
+
+	before := user
+
+	tx := dbc.Begin()
+	updateError := tx.Model(&user).Updates(payload).Error
+
+	if updateError == nil && emailChanged {
+		user.VerifiedAt.Valid = false
+		updateError = tx.Model(&user).UpdateColumn("verified_at", nil).Error
+	}
+
+	if updateError == nil {
+		updateError = audit.Record(tx, user, audit.ACTION_UPDATE, audit.RESOURCE_USER, user.UUID, before, user)
+	}
+
+	if updateError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not update User#%s.", user.UUID))
+		return
+	}
+
+	tx.Commit()
+
+	if emailChanged {
+		if sendError := sendUserToken(user, models.USER_TOKEN_PURPOSE_VERIFY); sendError != nil {
+			log.Printf("Could not send verification mail to User#%s: %s", user.UUID, sendError)
+		}
+	}
+
+	responders.Json().Success(ctx, user)
+	return
+}
+
+/**
+ *	Deletes authenticated user and ends every session of user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (mePrototype) Destroy(ctx *gin.Context) {
+	var user models.User
+
+	if !findMe(ctx, &user) {
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+	destroyError := identity.RotateAuthKey(tx, &user)
+
+	if destroyError == nil {
+		destroyError = tx.Delete(&user).Error
+	}
+
+	if destroyError == nil {
+		destroyError = audit.Record(tx, user, audit.ACTION_DESTROY, audit.RESOURCE_USER, user.UUID, user, nil)
+	}
+
+	if destroyError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not destroy resource User#%s.", user.UUID))
+		return
+	}
+
+	tx.Commit()
+
+	responders.NoContent(ctx)
+	return
+}
+
+/**
+ *	Changes password of authenticated user, other sessions are ended and new tokens are returned.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (mePrototype) Password(ctx *gin.Context) {
+	var user models.User
+	var payload models.PasswordChangePayload
+
+	if !findMe(ctx, &user) {
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	if !utils.PasswordMatch(user.Password, payload.CurrentPassword) {
+		responders.Text().Forbidden(ctx, "Current password is incorrect.")
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+	changeError := tx.Model(&user).UpdateColumn("password", utils.PasswordCreate(payload.Password)).Error
+
+	if changeError == nil {
+		changeError = identity.RotateAuthKey(tx, &user)
+	}
+
+	if changeError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not change password of User#%s.", user.UUID))
+		return
+	}
+
+	tx.Commit()
+
+	respondWithTokens(ctx, user)
+	return
+}
+
+/**
+ *	Exports data of authenticated user as a downloadable JSON document.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (mePrototype) Export(ctx *gin.Context) {
+	var user models.User
+	var identities models.UserIdentities
+	var apiKeys models.ApiKeys
+	var statements models.Statements
+	var votes []meVoteExport
+	var favourites models.StatementFavourites
+
+	if !findMe(ctx, &user) {
+		return
+	}
+
+	dbc := db.GetConnection()
+
+	dbc.Where("`user_id` = ?", user.ID).Find(&identities)
+	dbc.Where("`user_id` = ?", user.ID).Find(&apiKeys)
+	dbc.Unscoped().Preload("Category").Where("`user_id` = ?", user.ID).Find(&statements)
+	dbc.Table("statement_vote").
+		Select("`statement`.`uuid` AS `statement`, `statement_vote`.`value`, `statement_vote`.`created_at`").
+		Joins("INNER JOIN `statement` ON `statement`.`id` = `statement_vote`.`statement_id`").
+		Where("`statement_vote`.`user_id` = ?", user.ID).
+		Scan(&votes)
+	dbc.Preload("Statement").Where("`user_id` = ?", user.ID).Find(&favourites)
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"user-%s.json\"", user.UUID))
+
+	responders.Json().Success(ctx, responders.Response{
+		"exportedAt": time.Now(),
+		"user":       user,
+		"identities": identities,
+		"apiKeys":    apiKeys,
+		"statements": statements,
+		"votes":      votes,
+		"favourites": favourites,
+	})
+	return
+}
+
+func MeController() mePrototype {
+	var controllerInstance mePrototype
+	return controllerInstance
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	// Native packages
+	"strconv"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/scopes"
+	"jaha-api/utils"
+)
+
+type moderationPrototype struct{}
+
+/**
+ *	Lists statements pending moderation, oldest first.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (moderationPrototype) Index(ctx *gin.Context) {
+	var statements models.Statements
+	var collection models.Collection
+	var collectionCount int
+	var queryError error
+
+	user := identity.GetUser(ctx)
+
+	if !user.IsModerator() {
+		responders.Text().Forbidden(ctx, "Only moderators can see the moderation queue.")
+		return
+	}
+
+	params := ctx.Request.URL.Query()
+	paramPage, _ := strconv.Atoi(utils.Pick(params.Get("page"), "1"))
+
+	dbc := db.GetConnection().Scopes(scopes.Statement().Pending)
+
+	dbc.Model(&models.Statement{}).Count(&collectionCount)
+
+	collection = models.Collection{}
+	collection.SetLimit(COLLECTION_DEFAULT_LIMIT)
+	collection.Grab(nil, 1, collectionCount)
+	collection.SetPointer(paramPage)
+
+	queryError = dbc.Preload("Category").Order("`statement`.`created_at` ASC").Limit(collection.Limit).Offset(collection.GetOffset()).Find(&statements).Error
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	collection.Grab(statements, paramPage, collectionCount)
+
+	responders.Json().Success(ctx, collection)
+	return
+}
+
+func ModerationController() moderationPrototype {
+	var controllerInstance moderationPrototype
+	return controllerInstance
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	// Native packages
+	"log"
+
+	// 3rd party packages
+	"github.com/gin-gonic/contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/identity"
+	"jaha-api/oidc"
+	"jaha-api/responders"
+)
+
+type oidcPrototype struct{}
+
+/**
+ *	Redirects to OIDC provider, flow state is kept in session until callback.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (oidcPrototype) Login(ctx *gin.Context) {
+	provider := oidc.GetProvider()
+
+	if provider == nil {
+		responders.Text().NotImplemented(ctx, oidc.ErrNotConfigured.Error())
+		return
+	}
+
+	flow := oidc.NewFlow()
+	authorizationUrl, discoveryError := provider.AuthorizationUrl(flow)
+
+	if discoveryError != nil {
+		log.Printf("OIDC discovery failed: %s", discoveryError)
+		responders.ResponseText(ctx, 502, "Could not reach identity provider.")
+		return
+	}
+
+	session := sessions.Default(ctx)
+	session.Set("oidcState", flow.State)
+	session.Set("oidcNonce", flow.Nonce)
+	session.Set("oidcVerifier", flow.CodeVerifier)
+	session.Save()
+
+	ctx.Redirect(302, authorizationUrl)
+	return
+}
+
+/**
+ *	Completes OIDC login, links or creates user and responds with access and refresh tokens.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (oidcPrototype) Callback(ctx *gin.Context) {
+	provider := oidc.GetProvider()
+
+	if provider == nil {
+		responders.Text().NotImplemented(ctx, oidc.ErrNotConfigured.Error())
+		return
+	}
+
+	params := ctx.Request.URL.Query()
+	session := sessions.Default(ctx)
+
+	flow := oidc.Flow{}
+	flow.State, _ = session.Get("oidcState").(string)
+	flow.Nonce, _ = session.Get("oidcNonce").(string)
+	flow.CodeVerifier, _ = session.Get("oidcVerifier").(string)
+
+	// @NOTE Flow state is single-use, clear it whatever the outcome.
+	session.Delete("oidcState")
+	session.Delete("oidcNonce")
+	session.Delete("oidcVerifier")
+	session.Save()
+
+	if params.Get("error") != "" {
+		responders.Text().Unauthorized(ctx, "Identity provider denied login: "+params.Get("error"))
+		return
+	}
+
+	if flow.State == "" || params.Get("state") != flow.State {
+		responders.Text().BadRequest(ctx, "OIDC state is missing or does not match.")
+		return
+	}
+
+	if params.Get("code") == "" {
+		responders.Text().BadRequest(ctx, "OIDC authorization code is missing.")
+		return
+	}
+
+	claims, exchangeError := provider.Exchange(params.Get("code"), flow)
+
+	if exchangeError != nil {
+		log.Printf("OIDC code exchange failed: %s", exchangeError)
+		responders.Text().Unauthorized(ctx, "Could not verify identity provider login.")
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+	user, linkError := identity.FindOrCreateExternalUser(tx, provider.Name, claims)
+
+	if linkError == identity.ErrUnverifiedEmail {
+		tx.Rollback()
+		responders.Text().Conflict(ctx, "A user with this email already exists, verify the email with identity provider or log in with password.")
+		return
+	}
+
+	if linkError != nil {
+		tx.Rollback()
+		log.Printf("OIDC user provisioning failed: %s", linkError)
+		responders.Text().ServerError(ctx, "Could not link identity provider login.")
+		return
+	}
+
+	tx.Commit()
+
+	if user.IsSuspended() {
+		responders.Text().Forbidden(ctx, "User account is suspended.")
+		return
+	}
+
+	respondWithTokens(ctx, user)
+	return
+}
+
+func OidcController() oidcPrototype {
+	var controllerInstance oidcPrototype
+	return controllerInstance
+}
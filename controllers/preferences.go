@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	// Native packages
+	"strconv"
+
+	// 3rd party packages
+	"github.com/gin-gonic/contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/utils"
+)
+
+type preferencesPrototype struct{}
+
+/**
+ *	Returns client preferences, stored with API key or user for authenticated clients and in session for anonymous clients.
+ *	@NOTE Server-to-server clients have no cookies, their preferences follow their API key.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return models.Preferences
+ */
+func getPreferences(ctx *gin.Context) models.Preferences {
+	var preferences models.Preferences
+
+	if apiKey, hasApiKey := identity.GetApiKey(ctx); hasApiKey {
+		return models.Preferences{SafeMode: apiKey.SafeMode, MaxLevel: apiKey.MaxLevel}
+	}
+
+	if user := identity.GetUser(ctx); user.ID != 0 {
+		return models.Preferences{SafeMode: user.SafeMode, MaxLevel: user.MaxLevel}
+	}
+
+	session := sessions.Default(ctx)
+	preferences.SafeMode, _ = session.Get("safeMode").(bool)
+	preferences.MaxLevel, _ = session.Get("maxLevel").(int)
+
+	return preferences
+}
+
+/**
+ *	Stores client preferences, see getPreferences.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param preferences models.Preferences - Preferences to store.
+ *
+ *	@return error
+ */
+func setPreferences(ctx *gin.Context, preferences models.Preferences) error {
+	changes := map[string]interface{}{"safe_mode": preferences.SafeMode, "max_level": preferences.MaxLevel}
+
+	if apiKey, hasApiKey := identity.GetApiKey(ctx); hasApiKey {
+		return db.GetConnection().Model(&models.ApiKey{}).Where("`id` = ?", apiKey.ID).Updates(changes).Error
+	}
+
+	if user := identity.GetUser(ctx); user.ID != 0 {
+		return db.GetConnection().Model(&models.User{}).Where("`id` = ?", user.ID).Updates(changes).Error
+	}
+
+	session := sessions.Default(ctx)
+	session.Set("safeMode", preferences.SafeMode)
+	session.Set("maxLevel", preferences.MaxLevel)
+
+	return session.Save()
+}
+
+/**
+ *	Returns maximum statement level for request, 0 means no limit.
+ *	@NOTE "maxLevel" query parameter wins over client preferences, safe mode limits to models.LEVEL_FAMILY.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return int
+ */
+func requestMaxLevel(ctx *gin.Context) int {
+	paramMaxLevel, _ := strconv.Atoi(ctx.Query("maxLevel"))
+
+	if models.IsValidLevel(paramMaxLevel) {
+		return paramMaxLevel
+	}
+
+	preferences := getPreferences(ctx)
+
+	if preferences.SafeMode {
+		return models.LEVEL_FAMILY
+	}
+
+	if models.IsValidLevel(preferences.MaxLevel) {
+		return preferences.MaxLevel
+	}
+
+	return 0
+}
+
+/**
+ *	Retrieves client preferences.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (preferencesPrototype) Show(ctx *gin.Context) {
+	responders.Json().Success(ctx, getPreferences(ctx))
+	return
+}
+
+/**
+ *	Updates client preferences, preferences apply to every later request of the same client.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (preferencesPrototype) Update(ctx *gin.Context) {
+	var payload models.PreferencesPayload
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	preferences := models.Preferences{SafeMode: payload.SafeMode, MaxLevel: payload.MaxLevel}
+
+	if saveError := setPreferences(ctx, preferences); saveError != nil {
+		responders.Text().ServerError(ctx, "Could not save preferences, unknown error.")
+		return
+	}
+
+	responders.Json().Success(ctx, preferences)
+	return
+}
+
+func PreferencesController() preferencesPrototype {
+	var controllerInstance preferencesPrototype
+	return controllerInstance
+}
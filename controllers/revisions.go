@@ -0,0 +1,397 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"strconv"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+
+	// Local packages
+	"jaha-api/audit"
+	"jaha-api/db"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/revisions"
+	"jaha-api/utils"
+)
+
+type revisionsPrototype struct {
+	resourceType string
+}
+
+/**
+ *	Resource that revisions belong to.
+ */
+type revisionTarget struct {
+	ID       int
+	Name     string
+	Resource interface{}
+	Snapshot interface{}
+}
+
+/**
+ *	Finds resource by UUID, soft deleted resources keep their history. ID is 0 if not found.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param paramId string - Resource UUID.
+ *
+ *	@return revisionTarget, error
+ */
+func (controller revisionsPrototype) findTarget(dbc *gorm.DB, paramId string) (revisionTarget, error) {
+	if controller.resourceType == audit.RESOURCE_CATEGORY {
+		var category models.Category
+
+		queryError := dbc.Unscoped().Where("`uuid` = ?", paramId).First(&category).Error
+
+		return revisionTarget{ID: category.ID, Name: "Category#" + paramId, Resource: category, Snapshot: category.Snapshot()}, queryError
+	}
+
+	var statement models.Statement
+
+	queryError := dbc.Unscoped().Preload("Category").Where("`uuid` = ?", paramId).First(&statement).Error
+
+	return revisionTarget{ID: statement.ID, Name: "Statement#" + paramId, Resource: statement, Snapshot: statement.Snapshot()}, queryError
+}
+
+/**
+ *	Checks revision snapshot like an update of the resource, sends response and returns false if request cannot continue.
+ *	@NOTE Statements are checked for near-duplicates and their category has to exist, categories for a taken slug.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param target revisionTarget - Resource to roll back.
+ *	@param revision models.Revision - Revision to roll back to.
+ *
+ *	@return bool
+ */
+func (controller revisionsPrototype) checkRollback(ctx *gin.Context, dbc *gorm.DB, target revisionTarget, revision models.Revision) bool {
+	if controller.resourceType == audit.RESOURCE_CATEGORY {
+		var snapshot models.CategorySnapshot
+		var existing models.Category
+
+		if decodeError := revision.DecodeInto(&snapshot); decodeError != nil {
+			responders.Text().ServerError(ctx, decodeError.Error())
+			return false
+		}
+
+		dbc.Unscoped().Where("`slug` = ? AND `id` != ?", snapshot.Slug, target.ID).First(&existing)
+
+		if existing.ID != 0 {
+			responders.Text().Conflict(ctx, fmt.Sprintf("Could not roll back %s, slug %s is taken by Category#%s.", target.Name, snapshot.Slug, existing.UUID))
+			return false
+		}
+
+		return true
+	}
+
+	var snapshot models.StatementSnapshot
+	var category models.Category
+
+	statement := target.Resource.(models.Statement)
+
+	if decodeError := revision.DecodeInto(&snapshot); decodeError != nil {
+		responders.Text().ServerError(ctx, decodeError.Error())
+		return false
+	}
+
+	if snapshot.Category != "" && snapshot.Category != statement.Category.UUID {
+		dbc.Where("`uuid` = ?", snapshot.Category).First(&category)
+
+		if category.ID == 0 {
+			responders.Text().Conflict(ctx, fmt.Sprintf("Could not roll back %s, Category#%s no longer exists.", target.Name, snapshot.Category))
+			return false
+		}
+	}
+
+	if snapshot.Body != statement.Body && !checkSimilarStatements(ctx, snapshot.Body, statement.ID) {
+		return false
+	}
+
+	return true
+}
+
+/**
+ *	Restores resource properties from revision snapshot, returns resource after the change.
+ *	@NOTE Rolled back statements are reviewed again like edited ones, see resetStatementReview.
+ *
+ *	@param tx *gorm.DB - Transaction of the rollback.
+ *	@param user models.User - User rolling back.
+ *	@param target revisionTarget - Resource to roll back.
+ *	@param revision models.Revision - Revision to roll back to.
+ *
+ *	@return revisionTarget, error
+ */
+func (controller revisionsPrototype) rollback(tx *gorm.DB, user models.User, target revisionTarget, revision models.Revision) (revisionTarget, error) {
+	if controller.resourceType == audit.RESOURCE_CATEGORY {
+		var snapshot models.CategorySnapshot
+
+		category := target.Resource.(models.Category)
+
+		if decodeError := revision.DecodeInto(&snapshot); decodeError != nil {
+			return target, decodeError
+		}
+
+		updateError := tx.Model(&category).Unscoped().Updates(map[string]interface{}{
+			"name":          snapshot.Name,
+			"slug":          snapshot.Slug,
+			"default_level": snapshot.DefaultLevel,
+		}).Error
+
+		return revisionTarget{ID: category.ID, Name: target.Name, Resource: category, Snapshot: category.Snapshot()}, updateError
+	}
+
+	var snapshot models.StatementSnapshot
+	var category models.Category
+
+	statement := target.Resource.(models.Statement)
+
+	if decodeError := revision.DecodeInto(&snapshot); decodeError != nil {
+		return target, decodeError
+	}
+
+	changes := map[string]interface{}{
+		"body":  snapshot.Body,
+		"level": snapshot.Level,
+	}
+
+	if snapshot.Category != "" && snapshot.Category != statement.Category.UUID {
+		if queryError := tx.Where("`uuid` = ?", snapshot.Category).First(&category).Error; queryError != nil {
+			return target, queryError
+		}
+
+		changes["category_id"] = category.ID
+	}
+
+	// @NOTE Associations are not saved, Category is loaded only for responses.
+	updateError := tx.Set("gorm:save_associations", false).Model(&statement).Unscoped().Updates(changes).Error
+
+	if category.ID != 0 {
+		statement.Category = category
+	}
+
+	if updateError == nil {
+		updateError = resetStatementReview(tx, user, &statement)
+	}
+
+	return revisionTarget{ID: statement.ID, Name: target.Name, Resource: statement, Snapshot: statement.Snapshot()}, updateError
+}
+
+/**
+ *	Lists resource revisions, newest first.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (controller revisionsPrototype) Index(ctx *gin.Context) {
+	var revisionList models.Revisions
+	var users models.Users
+	var collection models.Collection
+	var collectionCount int
+
+	paramId := ctx.Param("uuid")
+	paramPage, _ := strconv.Atoi(utils.Pick(ctx.Query("page"), "1"))
+
+	dbc := db.GetConnection()
+	target, queryError := controller.findTarget(dbc, paramId)
+
+	if target.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("%s not found.", target.Name))
+		return
+	}
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	revisionQuery := dbc.Model(&models.Revision{}).Where("`resource_type` = ? AND `resource_id` = ?", controller.resourceType, target.ID)
+	revisionQuery.Count(&collectionCount)
+
+	collection = models.Collection{}
+	collection.SetLimit(COLLECTION_DEFAULT_LIMIT)
+	collection.Grab(nil, 1, collectionCount)
+	collection.SetPointer(paramPage)
+
+	queryError = revisionQuery.Order("`number` DESC").Limit(collection.Limit).Offset(collection.GetOffset()).Find(&revisionList).Error
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	userIds := []int64{}
+
+	for index := range revisionList {
+		revisionList[index].Decode()
+
+		if revisionList[index].UserId.Valid {
+			userIds = append(userIds, revisionList[index].UserId.Int64)
+		}
+	}
+
+	// @NOTE Users are loaded separately, soft deleted users are still shown.
+	if len(userIds) > 0 {
+		dbc.Unscoped().Where("`id` IN (?)", userIds).Find(&users)
+	}
+
+	for index := range revisionList {
+		for userIndex := range users {
+			if revisionList[index].UserId.Valid && int64(users[userIndex].ID) == revisionList[index].UserId.Int64 {
+				revisionList[index].User = &users[userIndex]
+			}
+		}
+	}
+
+	collection.Grab(revisionList, paramPage, collectionCount)
+
+	responders.Json().Success(ctx, collection)
+	return
+}
+
+/**
+ *	Returns changed properties between two revisions, set by "from" and "to" query params.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (controller revisionsPrototype) Diff(ctx *gin.Context) {
+	paramId := ctx.Param("uuid")
+	paramFrom, fromError := strconv.Atoi(ctx.Query("from"))
+	paramTo, toError := strconv.Atoi(ctx.Query("to"))
+
+	if fromError != nil || toError != nil {
+		responders.Text().BadRequest(ctx, "Parameters from and to must be revision numbers.")
+		return
+	}
+
+	dbc := db.GetConnection()
+	target, queryError := controller.findTarget(dbc, paramId)
+
+	if target.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("%s not found.", target.Name))
+		return
+	}
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	fromRevision := revisions.Find(dbc, controller.resourceType, target.ID, paramFrom)
+	toRevision := revisions.Find(dbc, controller.resourceType, target.ID, paramTo)
+
+	for _, revision := range []models.Revision{fromRevision, toRevision} {
+		if revision.ID == 0 {
+			responders.Text().NotFound(ctx, fmt.Sprintf("Revision of %s not found.", target.Name))
+			return
+		}
+	}
+
+	changes, diffError := revisions.Diff(fromRevision, toRevision)
+
+	if diffError != nil {
+		responders.Text().ServerError(ctx, diffError.Error())
+		return
+	}
+
+	responders.Json().Success(ctx, models.RevisionDiff{
+		From:    paramFrom,
+		To:      paramTo,
+		Changes: changes,
+	})
+	return
+}
+
+/**
+ *	Rolls resource back to an earlier revision, the rollback is kept as a new revision.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (controller revisionsPrototype) Rollback(ctx *gin.Context) {
+	var payload models.RevisionRollbackPayload
+
+	paramId := ctx.Param("uuid")
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	dbc := db.GetConnection()
+	target, queryError := controller.findTarget(dbc, paramId)
+
+	if target.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("%s not found.", target.Name))
+		return
+	}
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	revision := revisions.Find(dbc, controller.resourceType, target.ID, payload.Revision)
+
+	if revision.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Revision#%d of %s not found.", payload.Revision, target.Name))
+		return
+	}
+
+	if !controller.checkRollback(ctx, dbc, target, revision) {
+		return
+	}
+
+	user := identity.GetUser(ctx)
+
+	tx := dbc.Begin()
+	updated, rollbackError := controller.rollback(tx, user, target, revision)
+
+	if rollbackError == nil {
+		rollbackError = audit.Record(tx, user, audit.ACTION_ROLLBACK, controller.resourceType, paramId, target.Resource, updated.Resource)
+	}
+
+	if rollbackError == nil {
+		_, rollbackError = revisions.Record(tx, user, controller.resourceType, target.ID, target.Snapshot, updated.Snapshot, revision.Number)
+	}
+
+	// @NOTE A concurrent change may take the category slug between the check and update.
+	if db.IsDuplicateKeyError(rollbackError) {
+		tx.Rollback()
+		responders.Text().Conflict(ctx, fmt.Sprintf("Could not roll back %s, slug is taken.", target.Name))
+		return
+	}
+
+	if rollbackError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not roll back %s.", target.Name))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, updated.Resource)
+	return
+}
+
+func StatementRevisionsController() revisionsPrototype {
+	return revisionsPrototype{resourceType: audit.RESOURCE_STATEMENT}
+}
+
+func CategoryRevisionsController() revisionsPrototype {
+	return revisionsPrototype{resourceType: audit.RESOURCE_CATEGORY}
+}
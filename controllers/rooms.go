@@ -0,0 +1,723 @@
+package controllers
+
+import (
+	// Native packages
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/env"
+	"jaha-api/events"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/utils"
+	"jaha-api/websocket"
+)
+
+const ROOM_CODE_LENGTH = 6
+const ROOM_EVENTS_HEARTBEAT = 15 * time.Second
+
+const ROOM_EVENT_PLAYER_JOINED = "playerJoined"
+const ROOM_EVENT_NEXT_STATEMENT = "nextStatement"
+const ROOM_EVENT_ANSWER_RECORDED = "answerRecorded"
+const ROOM_EVENT_HEARTBEAT = "heartbeat"
+const ROOM_EVENT_JOINED = "joined"
+const ROOM_EVENT_RESUMED = "resumed"
+const ROOM_EVENT_ROOM = "room"
+const ROOM_EVENT_PONG = "pong"
+const ROOM_EVENT_ERROR = "error"
+
+const ROOM_ACTION_JOIN = "join"
+const ROOM_ACTION_RESUME = "resume"
+const ROOM_ACTION_NEXT = "next"
+const ROOM_ACTION_ANSWER = "answer"
+const ROOM_ACTION_PING = "ping"
+
+type roomsPrototype struct{}
+
+type roomActionError struct {
+	Status  int
+	Message string
+	Issues  []string
+}
+
+/**
+ *	Finds room by join code, preloads game, roster and current statement.
+ *
+ *	@param dbc *gorm.DB - Connection or transaction to query with.
+ *	@param roomCode string - Room join code, case insensitive.
+ *
+ *	@return models.Room, error
+ */
+func findRoom(dbc *gorm.DB, roomCode string) (models.Room, error) {
+	var room models.Room
+	var statement models.Statement
+	var queryError error
+
+	queryError = dbc.Preload("Game").Preload("Game.Categories").Preload("Players").Where("`code` = ?", strings.ToUpper(roomCode)).First(&room).Error
+
+	if room.ID == 0 || queryError != nil {
+		return room, queryError
+	}
+
+	if room.StatementId.Valid {
+		queryError = dbc.Preload("Category").First(&statement, room.StatementId.Int64).Error
+		room.Statement = &statement
+	}
+
+	return room, queryError
+}
+
+/**
+ *	Retrieves published resource.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (roomsPrototype) Show(ctx *gin.Context) {
+	paramCode := ctx.Param("code")
+	room, queryError := findRoom(db.GetConnection(), paramCode)
+
+	if room.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Room#%s not found.", paramCode))
+		return
+	}
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	responders.Json().Success(ctx, room)
+	return
+}
+
+/**
+ *	Creates a new resource, statements are drawn from an existing game.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (roomsPrototype) Create(ctx *gin.Context) {
+	var payload models.RoomPayload
+	var room models.Room
+	var game models.Game
+	var existing models.Room
+	var createError error
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	dbc := db.GetConnection()
+	dbc.Preload("Categories").Where("`uuid` = ?", payload.Game).First(&game)
+
+	if game.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Game#%s not found.", payload.Game))
+		return
+	}
+
+	room = models.Room{
+		UUID:   utils.RandomString(8),
+		Game:   game,
+		GameId: game.ID,
+	}
+
+	// @NOTE Join codes are short, retry a few times on collision.
+	for attempt := 0; attempt < 5; attempt++ {
+		existing = models.Room{}
+		room.Code = utils.RandomCode(ROOM_CODE_LENGTH)
+
+		dbc.Unscoped().Where("`code` = ?", room.Code).First(&existing)
+
+		if existing.ID == 0 {
+			break
+		}
+	}
+
+	if existing.ID != 0 {
+		responders.Text().ServerError(ctx, "Could not create resource, no join code available.")
+		return
+	}
+
+	if !room.Valid() {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": room.GetErrors(),
+		})
+		return
+	}
+
+	createError = dbc.Set("gorm:save_associations", false).Create(&room).Error
+
+	if createError != nil {
+		responders.Text().ServerError(ctx, "Could not create resource, unknown error.")
+		return
+	}
+
+	responders.Json().Success(ctx, room)
+	return
+}
+
+/**
+ *	Sends room action error response, validation issues are included when present.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (actionError *roomActionError) Respond(ctx *gin.Context) {
+	if len(actionError.Issues) > 0 {
+		responders.ResponseObject(ctx, actionError.Status, responders.Response{
+			"error":  actionError.Message,
+			"issues": actionError.Issues,
+		})
+		return
+	}
+
+	responders.ResponseText(ctx, actionError.Status, actionError.Message)
+}
+
+/**
+ *	Adds player to room roster and publishes ROOM_EVENT_PLAYER_JOINED.
+ *
+ *	@param roomCode string - Room join code.
+ *	@param playerName string - Name shown in roster, unique per room.
+ *	@param user models.User - Authenticated user, user ID is 0 for guests.
+ *
+ *	@return models.Room, models.RoomPlayer, *roomActionError
+ */
+func joinRoom(roomCode string, playerName string, user models.User) (models.Room, models.RoomPlayer, *roomActionError) {
+	var player models.RoomPlayer
+	var createError error
+
+	dbc := db.GetConnection()
+	room, queryError := findRoom(dbc, roomCode)
+
+	if room.ID == 0 {
+		return room, player, &roomActionError{Status: 404, Message: fmt.Sprintf("Room#%s not found.", roomCode)}
+	}
+
+	if queryError != nil {
+		return room, player, &roomActionError{Status: 500, Message: queryError.Error()}
+	}
+
+	player = models.RoomPlayer{
+		UUID:   utils.RandomString(8),
+		RoomId: room.ID,
+		Name:   strings.TrimSpace(playerName),
+		Token:  utils.RandomString(16),
+	}
+
+	if user.ID != 0 {
+		player.UserId = null.IntFrom(int64(user.ID))
+	}
+
+	if !player.Valid() {
+		return room, player, &roomActionError{Status: 400, Message: "Resource validation failed, see issues", Issues: player.GetErrors()}
+	}
+
+	if room.HasPlayerName(player.Name) {
+		return room, player, &roomActionError{Status: 409, Message: fmt.Sprintf("Could not join Room#%s, name %s is taken.", room.Code, player.Name)}
+	}
+
+	createError = dbc.Create(&player).Error
+
+	// @NOTE A concurrent join may take the name between the roster check and insert.
+	if db.IsDuplicateKeyError(createError) {
+		return room, player, &roomActionError{Status: 409, Message: fmt.Sprintf("Could not join Room#%s, name %s is taken.", room.Code, player.Name)}
+	}
+
+	if createError != nil {
+		return room, player, &roomActionError{Status: 500, Message: fmt.Sprintf("Could not join Room#%s.", room.Code)}
+	}
+
+	room.Players = append(room.Players, player)
+
+	events.GetHub().Publish(room.GetTopic(), ROOM_EVENT_PLAYER_JOINED, responders.Response{
+		"player": player,
+	})
+
+	return room, player, nil
+}
+
+/**
+ *	Draws next statement from room game deck, makes it the current statement and publishes ROOM_EVENT_NEXT_STATEMENT.
+ *
+ *	@param roomCode string - Room join code.
+ *	@param credentials models.RoomPlayerCredentials - Credentials of a player in room.
+ *
+ *	@return models.Room, *roomActionError
+ */
+func drawRoomStatement(roomCode string, credentials models.RoomPlayerCredentials) (models.Room, *roomActionError) {
+	var statement models.Statement
+	var drawError error
+
+	tx := db.GetConnection().Begin()
+
+	// @NOTE Lock room row so concurrent draws cannot skip statements.
+	room, queryError := findRoom(tx.Set("gorm:query_option", "FOR UPDATE"), roomCode)
+
+	if room.ID == 0 {
+		tx.Rollback()
+		return room, &roomActionError{Status: 404, Message: fmt.Sprintf("Room#%s not found.", roomCode)}
+	}
+
+	if queryError != nil {
+		tx.Rollback()
+		return room, &roomActionError{Status: 500, Message: queryError.Error()}
+	}
+
+	if _, isPlayer := room.FindPlayer(credentials.Player, credentials.Token); !isPlayer {
+		tx.Rollback()
+		return room, &roomActionError{Status: 403, Message: fmt.Sprintf("Player is not in Room#%s.", room.Code)}
+	}
+
+	statement, drawError = drawGameStatement(tx, &room.Game)
+
+	if drawError == nil && statement.ID == 0 {
+		tx.Rollback()
+		return room, &roomActionError{Status: 409, Message: fmt.Sprintf("Room#%s deck is exhausted.", room.Code)}
+	}
+
+	if drawError == nil {
+		drawError = tx.Set("gorm:save_associations", false).Model(&room).Update("statement_id", statement.ID).Error
+	}
+
+	if drawError != nil {
+		tx.Rollback()
+		return room, &roomActionError{Status: 500, Message: fmt.Sprintf("Could not draw statement for Room#%s.", room.Code)}
+	}
+
+	tx.Commit()
+
+	room.StatementId = null.IntFrom(int64(statement.ID))
+	room.Statement = &statement
+
+	events.GetHub().Publish(room.GetTopic(), ROOM_EVENT_NEXT_STATEMENT, responders.Response{
+		"statement": statement,
+	})
+
+	return room, nil
+}
+
+/**
+ *	Records player answer for current statement, updates player tally and publishes ROOM_EVENT_ANSWER_RECORDED.
+ *
+ *	@param roomCode string - Room join code.
+ *	@param payload models.RoomAnswerPayload - Player credentials and answer.
+ *
+ *	@return models.Room, *roomActionError
+ */
+func answerRoomStatement(roomCode string, payload models.RoomAnswerPayload) (models.Room, *roomActionError) {
+	var answer models.RoomAnswer
+	var existing models.RoomAnswer
+	var answerError error
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		return models.Room{}, &roomActionError{Status: 400, Message: "Resource validation failed, see issues", Issues: validationErrors}
+	}
+
+	tx := db.GetConnection().Begin()
+	room, queryError := findRoom(tx.Set("gorm:query_option", "FOR UPDATE"), roomCode)
+
+	if room.ID == 0 {
+		tx.Rollback()
+		return room, &roomActionError{Status: 404, Message: fmt.Sprintf("Room#%s not found.", roomCode)}
+	}
+
+	if queryError != nil {
+		tx.Rollback()
+		return room, &roomActionError{Status: 500, Message: queryError.Error()}
+	}
+
+	player, isPlayer := room.FindPlayer(payload.Player, payload.Token)
+
+	if !isPlayer {
+		tx.Rollback()
+		return room, &roomActionError{Status: 403, Message: fmt.Sprintf("Player is not in Room#%s.", room.Code)}
+	}
+
+	if room.Statement == nil {
+		tx.Rollback()
+		return room, &roomActionError{Status: 409, Message: fmt.Sprintf("Room#%s has no current statement.", room.Code)}
+	}
+
+	tx.Where("`room_player_id` = ? AND `statement_id` = ?", player.ID, room.Statement.ID).First(&existing)
+
+	if existing.ID != 0 {
+		tx.Rollback()
+		return room, &roomActionError{Status: 409, Message: fmt.Sprintf("Player#%s already answered Statement#%s.", player.UUID, room.Statement.UUID)}
+	}
+
+	answer = models.RoomAnswer{
+		RoomId:       room.ID,
+		RoomPlayerId: player.ID,
+		StatementId:  room.Statement.ID,
+		Answer:       payload.Answer,
+	}
+
+	answerError = tx.Create(&answer).Error
+
+	if answerError == nil {
+		player.Tally(answer.Answer)
+		answerError = tx.Model(&player).Updates(map[string]interface{}{
+			"have_count":       player.HaveCount,
+			"have_never_count": player.HaveNeverCount,
+		}).Error
+	}
+
+	if answerError != nil {
+		tx.Rollback()
+		return room, &roomActionError{Status: 500, Message: fmt.Sprintf("Could not record answer in Room#%s.", room.Code)}
+	}
+
+	tx.Commit()
+
+	for index := range room.Players {
+		if room.Players[index].ID == player.ID {
+			room.Players[index] = player
+		}
+	}
+
+	events.GetHub().Publish(room.GetTopic(), ROOM_EVENT_ANSWER_RECORDED, responders.Response{
+		"player":    player,
+		"statement": room.Statement,
+		"answer":    answer.Answer,
+	})
+
+	return room, nil
+}
+
+/**
+ *	Adds player to room roster, guests join without a user account.
+ *	@NOTE Player token is only exposed here and is required for answering.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (roomsPrototype) Join(ctx *gin.Context) {
+	var payload models.RoomPlayerPayload
+
+	ctx.BindJSON(&payload)
+
+	room, player, actionError := joinRoom(ctx.Param("code"), payload.Name, identity.GetUser(ctx))
+
+	if actionError != nil {
+		actionError.Respond(ctx)
+		return
+	}
+
+	responders.Json().Success(ctx, responders.Response{
+		"room":   room,
+		"player": player,
+		"token":  player.Token,
+	})
+	return
+}
+
+/**
+ *	Draws next statement from room game deck and makes it the current statement.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (roomsPrototype) Next(ctx *gin.Context) {
+	var payload models.RoomPlayerCredentials
+
+	ctx.BindJSON(&payload)
+
+	room, actionError := drawRoomStatement(ctx.Param("code"), payload)
+
+	if actionError != nil {
+		actionError.Respond(ctx)
+		return
+	}
+
+	responders.Json().Success(ctx, room)
+	return
+}
+
+/**
+ *	Records player answer for current statement and updates player tally.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (roomsPrototype) Answer(ctx *gin.Context) {
+	var payload models.RoomAnswerPayload
+
+	ctx.BindJSON(&payload)
+
+	room, actionError := answerRoomStatement(ctx.Param("code"), payload)
+
+	if actionError != nil {
+		actionError.Respond(ctx)
+		return
+	}
+
+	responders.Json().Success(ctx, room)
+	return
+}
+
+/**
+ *	Streams room updates as Server-Sent Events until client disconnects.
+ *	@NOTE Heartbeats are sent every ROOM_EVENTS_HEARTBEAT to keep proxies from closing idle streams.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (roomsPrototype) Events(ctx *gin.Context) {
+	paramCode := ctx.Param("code")
+	room, queryError := findRoom(db.GetConnection(), paramCode)
+
+	if room.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Room#%s not found.", paramCode))
+		return
+	}
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	subscription := events.GetHub().Subscribe(room.GetTopic())
+	defer events.GetHub().Unsubscribe(subscription)
+
+	heartbeat := time.NewTicker(ROOM_EVENTS_HEARTBEAT)
+	defer heartbeat.Stop()
+
+	clientGone := ctx.Writer.CloseNotify()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	ctx.Stream(func(writer io.Writer) bool {
+		select {
+		case <-clientGone:
+			return false
+		case event, isOpen := <-subscription.Events:
+			if !isOpen {
+				return false
+			}
+
+			ctx.SSEvent(event.Name, event.Data)
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent(ROOM_EVENT_HEARTBEAT, time.Now().Unix())
+			return true
+		}
+	})
+}
+
+/**
+ *	Writes room socket event message.
+ *
+ *	@param connection *websocket.Conn
+ *	@param eventName string
+ *	@param eventData interface{}
+ *
+ *	@return error
+ */
+func writeRoomSocketEvent(connection *websocket.Conn, eventName string, eventData interface{}) error {
+	return connection.WriteJSON(responders.Response{
+		"event": eventName,
+		"data":  eventData,
+	})
+}
+
+/**
+ *	Writes room action error as ROOM_EVENT_ERROR socket event.
+ *
+ *	@param connection *websocket.Conn
+ *	@param actionError *roomActionError
+ *
+ *	@return error
+ */
+func writeRoomSocketError(connection *websocket.Conn, actionError *roomActionError) error {
+	return writeRoomSocketEvent(connection, ROOM_EVENT_ERROR, responders.Response{
+		"status": actionError.Status,
+		"error":  actionError.Message,
+		"issues": actionError.Issues,
+	})
+}
+
+/**
+ *	Bidirectional room transport over WebSocket, see docs/rooms-websocket.md for message protocol.
+ *	@NOTE Room events are pushed the same way as in Events, actions are answered on the same socket.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (roomsPrototype) Socket(ctx *gin.Context) {
+	var credentials models.RoomPlayerCredentials
+
+	paramCode := ctx.Param("code")
+	room, queryError := findRoom(db.GetConnection(), paramCode)
+
+	if room.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Room#%s not found.", paramCode))
+		return
+	}
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	roomCode := room.Code
+	user := identity.GetUser(ctx)
+	connection, upgradeError := websocket.Upgrade(ctx.Writer, ctx.Request, env.GetWebSocketOrigins())
+
+	if upgradeError == websocket.ErrBadOrigin {
+		responders.Text().Forbidden(ctx, upgradeError.Error())
+		return
+	}
+
+	if upgradeError != nil {
+		responders.Text().BadRequest(ctx, upgradeError.Error())
+		return
+	}
+
+	defer connection.Close()
+
+	// @NOTE Clients answer heartbeat pings, a silent client is dropped after two missed heartbeats.
+	connection.ReadTimeout = 2 * ROOM_EVENTS_HEARTBEAT
+
+	subscription := events.GetHub().Subscribe(room.GetTopic())
+	defer events.GetHub().Unsubscribe(subscription)
+
+	readerDone := make(chan bool)
+	defer close(readerDone)
+
+	go func() {
+		heartbeat := time.NewTicker(ROOM_EVENTS_HEARTBEAT)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-readerDone:
+				return
+			case event, isOpen := <-subscription.Events:
+				if !isOpen || writeRoomSocketEvent(connection, event.Name, event.Data) != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if connection.WriteMessage(websocket.OPCODE_PING, nil) != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		var message models.RoomSocketMessage
+		var actionError *roomActionError
+
+		_, messageBytes, readError := connection.ReadMessage()
+
+		if readError != nil {
+			return
+		}
+
+		if json.Unmarshal(messageBytes, &message) != nil {
+			writeRoomSocketError(connection, &roomActionError{Status: 400, Message: "Message cannot be empty or malformed."})
+			continue
+		}
+
+		switch message.Action {
+		case ROOM_ACTION_PING:
+			writeRoomSocketEvent(connection, ROOM_EVENT_PONG, time.Now().Unix())
+			break
+		case ROOM_ACTION_JOIN:
+			joinedRoom, player, joinError := joinRoom(roomCode, message.Name, user)
+			actionError = joinError
+
+			if actionError == nil {
+				credentials = models.RoomPlayerCredentials{Player: player.UUID, Token: player.Token}
+				writeRoomSocketEvent(connection, ROOM_EVENT_JOINED, responders.Response{
+					"room":   joinedRoom,
+					"player": player,
+					"token":  player.Token,
+				})
+			}
+			break
+		case ROOM_ACTION_RESUME:
+			resumedRoom, _ := findRoom(db.GetConnection(), roomCode)
+			player, isPlayer := resumedRoom.FindPlayer(message.Player, message.Token)
+
+			if !isPlayer {
+				actionError = &roomActionError{Status: 403, Message: fmt.Sprintf("Player is not in Room#%s.", roomCode)}
+				break
+			}
+
+			credentials = models.RoomPlayerCredentials{Player: player.UUID, Token: player.Token}
+			writeRoomSocketEvent(connection, ROOM_EVENT_RESUMED, responders.Response{
+				"room":   resumedRoom,
+				"player": player,
+			})
+			break
+		case ROOM_ACTION_NEXT:
+			nextRoom, nextError := drawRoomStatement(roomCode, credentials)
+			actionError = nextError
+
+			if actionError == nil {
+				writeRoomSocketEvent(connection, ROOM_EVENT_ROOM, nextRoom)
+			}
+			break
+		case ROOM_ACTION_ANSWER:
+			answeredRoom, answerError := answerRoomStatement(roomCode, models.RoomAnswerPayload{
+				Player: credentials.Player,
+				Token:  credentials.Token,
+				Answer: message.Answer,
+			})
+			actionError = answerError
+
+			if actionError == nil {
+				writeRoomSocketEvent(connection, ROOM_EVENT_ROOM, answeredRoom)
+			}
+			break
+		default:
+			actionError = &roomActionError{Status: 400, Message: fmt.Sprintf("Unknown action \"%s\".", message.Action)}
+			break
+		}
+
+		if actionError != nil {
+			writeRoomSocketError(connection, actionError)
+		}
+	}
+}
+
+func RoomsController() roomsPrototype {
+	var controllerInstance roomsPrototype
+	return controllerInstance
+}
@@ -0,0 +1,487 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"net/http"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/audit"
+	"jaha-api/db"
+	"jaha-api/env"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/permissions"
+	"jaha-api/responders"
+	"jaha-api/revisions"
+	"jaha-api/utils"
+)
+
+type statementBatchesPrototype struct{}
+
+/**
+ *	Runs batch items in one transaction and sends per-item results.
+ *	@NOTE Each item runs in a savepoint, failed items are rolled back on their own so that partial batches keep the rest.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param mode string - STATEMENT_BATCH_MODE_ATOMIC or STATEMENT_BATCH_MODE_PARTIAL, empty is atomic.
+ *	@param itemCount int - Number of batch items.
+ *	@param runItem func(tx *gorm.DB, index int) models.StatementBatchResult - Runs item at index.
+ *
+ *	@return void
+ */
+func runStatementBatch(ctx *gin.Context, mode string, itemCount int, runItem func(tx *gorm.DB, index int) models.StatementBatchResult) {
+	response := models.StatementBatchResponse{
+		Mode:    utils.Pick(mode, models.STATEMENT_BATCH_MODE_ATOMIC),
+		Results: models.StatementBatchResults{},
+	}
+
+	tx := db.GetConnection().Begin()
+
+	for index := 0; index < itemCount; index++ {
+		if savepointError := tx.Exec("SAVEPOINT `batch_item`").Error; savepointError != nil {
+			tx.Rollback()
+			responders.Text().ServerError(ctx, savepointError.Error())
+			return
+		}
+
+		var savepointError error
+
+		result := runItem(tx, index)
+		result.Index = index
+
+		if result.Failed() {
+			savepointError = tx.Exec("ROLLBACK TO SAVEPOINT `batch_item`").Error
+			response.Failed++
+		} else {
+			savepointError = tx.Exec("RELEASE SAVEPOINT `batch_item`").Error
+			response.Succeeded++
+		}
+
+		// @NOTE MySQL rolls back the whole transaction on deadlocks, later items would run and commit outside of it.
+		if savepointError != nil {
+			tx.Rollback()
+			responders.Text().ServerError(ctx, "Could not save batch, transaction was aborted.")
+			return
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	// @NOTE Atomic batches still run every item, so that all failures are reported at once. Status is the worst item status.
+	if response.Mode == models.STATEMENT_BATCH_MODE_ATOMIC && response.Failed > 0 {
+		tx.Rollback()
+		responders.ResponseObject(ctx, response.Results.WorstStatus(), responders.Response{
+			"error":   "Batch failed, no statements were changed, see results",
+			"results": response.Results,
+		})
+		return
+	}
+
+	if commitError := tx.Commit().Error; commitError != nil {
+		responders.Text().ServerError(ctx, "Could not save batch, unknown error.")
+		return
+	}
+
+	responders.Json().Success(ctx, response)
+}
+
+/**
+ *	Returns true if near-duplicates may be saved, sends response and returns false if request cannot continue.
+ *	@NOTE Same rules as checkSimilarStatements, "force=true" is only allowed for moderators.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return bool, bool
+ */
+func batchForce(ctx *gin.Context) (bool, bool) {
+	if ctx.Query("force") != "true" {
+		return false, true
+	}
+
+	if user := identity.GetUser(ctx); !user.IsModerator() {
+		responders.Text().Forbidden(ctx, "Only moderators can force near-duplicate statements.")
+		return true, false
+	}
+
+	return true, true
+}
+
+/**
+ *	Finds statements similar to body, both stored and saved earlier in the same batch.
+ *	@NOTE Full-text indexes only see committed rows, earlier batch items are compared directly.
+ *
+ *	@param tx *gorm.DB - Batch transaction.
+ *	@param body string - Statement body to compare with.
+ *	@param excludeId int - Statement ID to leave out, 0 when creating.
+ *	@param batchStatements models.Statements - Statements saved earlier in the batch.
+ *
+ *	@return models.StatementDuplicates, error
+ */
+func findSimilarBatchStatements(tx *gorm.DB, body string, excludeId int, batchStatements models.Statements) (models.StatementDuplicates, error) {
+	duplicates, queryError := findSimilarStatements(tx, body, excludeId)
+
+	if queryError != nil {
+		return duplicates, queryError
+	}
+
+	for _, batchStatement := range batchStatements {
+		if batchStatement.ID == excludeId {
+			continue
+		}
+
+		if similarity := utils.Similarity(body, batchStatement.Body); similarity >= models.STATEMENT_DUPLICATE_THRESHOLD {
+			duplicates = append(duplicates, models.StatementDuplicate{
+				Statement:  batchStatement,
+				Similarity: similarity,
+			})
+		}
+	}
+
+	return duplicates, nil
+}
+
+/**
+ *	Returns true if user may act on statement of batch item, same rule as the single statement endpoint.
+ *	@NOTE Batch routes let any authenticated user in, moderators act on every statement and owners on their own.
+ *	@NOTE Permissions are only enforced in production, see middlewares.Permissions.
+ *
+ *	@param user models.User - User running the batch.
+ *	@param action string - One of permissions.ACTION_* constants.
+ *	@param statementId string - Statement UUID.
+ *
+ *	@return bool
+ */
+func canBatchItem(user models.User, action string, statementId string) bool {
+	return !env.IsProductionMode() || permissions.Can(user, permissions.RESOURCE_STATEMENT, action, map[string]string{"uuid": statementId})
+}
+
+/**
+ *	Returns failed batch item result.
+ *
+ *	@param status int - HTTP status the item would get on its own.
+ *	@param message string - Error message.
+ *
+ *	@return models.StatementBatchResult
+ */
+func failedBatchItem(status int, message string) models.StatementBatchResult {
+	return models.StatementBatchResult{Status: status, Error: message}
+}
+
+/**
+ *	Returns failed batch item result with validation issues, see utils.Validate.
+ *
+ *	@param validationErrors []string - Validation issues.
+ *
+ *	@return models.StatementBatchResult
+ */
+func invalidBatchItem(validationErrors []string) models.StatementBatchResult {
+	return models.StatementBatchResult{
+		Status: http.StatusBadRequest,
+		Error:  "Resource validation failed, see issues",
+		Issues: validationErrors,
+	}
+}
+
+/**
+ *	Creates resources from "items", each item is checked like a single Create.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (statementBatchesPrototype) Create(ctx *gin.Context) {
+	var payload models.StatementBatchCreatePayload
+	var batchStatements models.Statements
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	force, canContinue := batchForce(ctx)
+
+	if !canContinue {
+		return
+	}
+
+	user := identity.GetUser(ctx)
+	categories := map[string]models.Category{}
+
+	runStatementBatch(ctx, payload.Mode, len(payload.Items), func(tx *gorm.DB, index int) models.StatementBatchResult {
+		var existing models.Statement
+
+		item := payload.Items[index]
+
+		if itemError, itemErrors := utils.Validate(item); itemError != nil {
+			return invalidBatchItem(itemErrors)
+		}
+
+		tx.Unscoped().Where("`body` = ?", item.Body).First(&existing)
+
+		if existing.ID != 0 {
+			return failedBatchItem(http.StatusBadRequest, fmt.Sprintf("Could not create resource, Statement#%s already exists.", existing.UUID))
+		}
+
+		if item.Category == "" {
+			return failedBatchItem(http.StatusBadRequest, "Could not create resource, Category#<UUID> missing.")
+		}
+
+		if !force {
+			duplicates, queryError := findSimilarBatchStatements(tx, item.Body, 0, batchStatements)
+
+			if queryError != nil {
+				return failedBatchItem(http.StatusInternalServerError, queryError.Error())
+			}
+
+			if len(duplicates) > 0 {
+				result := failedBatchItem(http.StatusConflict, "Statement is too similar to existing statements, see duplicates")
+				result.Duplicates = duplicates
+				return result
+			}
+		}
+
+		category, hasCategory := categories[item.Category]
+
+		if !hasCategory {
+			tx.Where("`uuid` = ?", item.Category).First(&category)
+
+			if category.ID == 0 {
+				return failedBatchItem(http.StatusNotFound, fmt.Sprintf("Category#%s not found.", item.Category))
+			}
+
+			categories[item.Category] = category
+		}
+
+		statement := models.Statement{
+			UUID:       utils.RandomString(8),
+			Body:       item.Body,
+			Status:     models.STATEMENT_STATUS_DRAFT,
+			Category:   category,
+			CategoryId: category.ID,
+		}
+
+		if user.ID != 0 {
+			statement.UserId = null.IntFrom(int64(user.ID))
+		}
+
+		if item.Level != 0 {
+			statement.Level = null.IntFrom(int64(item.Level))
+		}
+
+		createError := tx.Set("gorm:save_associations", false).Create(&statement).Error
+
+		if createError == nil {
+			createError = audit.Record(tx, user, audit.ACTION_CREATE, audit.RESOURCE_STATEMENT, statement.UUID, nil, statement)
+		}
+
+		if createError == nil {
+			_, createError = revisions.Record(tx, user, audit.RESOURCE_STATEMENT, statement.ID, nil, statement.Snapshot(), 0)
+		}
+
+		if createError != nil {
+			return failedBatchItem(http.StatusInternalServerError, "Could not create resource, unknown error.")
+		}
+
+		batchStatements = append(batchStatements, statement)
+
+		return models.StatementBatchResult{Status: http.StatusCreated, Statement: &statement}
+	})
+}
+
+/**
+ *	Updates resources from "items" identified by "uuid", each item is checked like a single Update.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (statementBatchesPrototype) Update(ctx *gin.Context) {
+	var payload models.StatementBatchUpdatePayload
+	var batchStatements models.Statements
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	force, canContinue := batchForce(ctx)
+
+	if !canContinue {
+		return
+	}
+
+	user := identity.GetUser(ctx)
+
+	runStatementBatch(ctx, payload.Mode, len(payload.Items), func(tx *gorm.DB, index int) models.StatementBatchResult {
+		var statement models.Statement
+		var category models.Category
+
+		item := payload.Items[index]
+
+		if itemError, itemErrors := utils.Validate(item); itemError != nil {
+			return invalidBatchItem(itemErrors)
+		}
+
+		if item.StatementPayload == (models.StatementPayload{}) {
+			return failedBatchItem(http.StatusBadRequest, "Payload cannot be empty or malformed.")
+		}
+
+		if !canBatchItem(user, permissions.ACTION_UPDATE, item.UUID) {
+			return failedBatchItem(http.StatusForbidden, "Permission denied.")
+		}
+
+		tx.Unscoped().Preload("Category").Where("`uuid` = ?", item.UUID).First(&statement)
+
+		if statement.ID == 0 {
+			return failedBatchItem(http.StatusNotFound, fmt.Sprintf("Statement#%s not found.", item.UUID))
+		}
+
+		if item.Body != "" && !force {
+			duplicates, queryError := findSimilarBatchStatements(tx, item.Body, statement.ID, batchStatements)
+
+			if queryError != nil {
+				return failedBatchItem(http.StatusInternalServerError, queryError.Error())
+			}
+
+			if len(duplicates) > 0 {
+				result := failedBatchItem(http.StatusConflict, "Statement is too similar to existing statements, see duplicates")
+				result.Duplicates = duplicates
+				return result
+			}
+		}
+
+		changes := map[string]interface{}{}
+
+		if item.Body != "" {
+			changes["body"] = item.Body
+		}
+
+		if item.Level != 0 {
+			changes["level"] = null.IntFrom(int64(item.Level))
+		}
+
+		if item.Category != "" {
+			tx.Where("`uuid` = ?", item.Category).First(&category)
+
+			if category.ID == 0 {
+				return failedBatchItem(http.StatusNotFound, fmt.Sprintf("Category#%s not found.", item.Category))
+			}
+
+			changes["category_id"] = category.ID
+		}
+
+		before := statement
+
+		updateError := tx.Set("gorm:save_associations", false).Model(&statement).Unscoped().Updates(changes).Error
+
+		if category.ID != 0 {
+			statement.Category = category
+		}
+
+		if updateError == nil {
+			updateError = resetStatementReview(tx, user, &statement)
+		}
+
+		if updateError == nil {
+			updateError = audit.Record(tx, user, audit.ACTION_UPDATE, audit.RESOURCE_STATEMENT, statement.UUID, before, statement)
+		}
+
+		if updateError == nil {
+			_, updateError = revisions.Record(tx, user, audit.RESOURCE_STATEMENT, statement.ID, before.Snapshot(), statement.Snapshot(), 0)
+		}
+
+		if updateError != nil {
+			return failedBatchItem(http.StatusInternalServerError, fmt.Sprintf("Could not update Statement#%s.", item.UUID))
+		}
+
+		batchStatements = append(batchStatements, statement)
+
+		return models.StatementBatchResult{Status: http.StatusOK, Statement: &statement}
+	})
+}
+
+/**
+ *	Destroys resources from "items" identified by "uuid".
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (statementBatchesPrototype) Destroy(ctx *gin.Context) {
+	var payload models.StatementBatchDestroyPayload
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	user := identity.GetUser(ctx)
+
+	runStatementBatch(ctx, payload.Mode, len(payload.Items), func(tx *gorm.DB, index int) models.StatementBatchResult {
+		var statement models.Statement
+
+		item := payload.Items[index]
+
+		if itemError, itemErrors := utils.Validate(item); itemError != nil {
+			return invalidBatchItem(itemErrors)
+		}
+
+		if !canBatchItem(user, permissions.ACTION_DESTROY, item.UUID) {
+			return failedBatchItem(http.StatusForbidden, "Permission denied.")
+		}
+
+		tx.Unscoped().Where("`uuid` = ?", item.UUID).First(&statement)
+
+		if statement.ID == 0 {
+			return failedBatchItem(http.StatusNotFound, fmt.Sprintf("Statement#%s not found.", item.UUID))
+		}
+
+		if statement.DeletedAt.Valid {
+			return failedBatchItem(http.StatusConflict, fmt.Sprintf("Statement#%s already destroyed.", item.UUID))
+		}
+
+		destroyError := tx.Delete(&statement).Error
+
+		if destroyError == nil {
+			destroyError = audit.Record(tx, user, audit.ACTION_DESTROY, audit.RESOURCE_STATEMENT, statement.UUID, statement, nil)
+		}
+
+		if destroyError != nil {
+			return failedBatchItem(http.StatusInternalServerError, fmt.Sprintf("Could not destroy resource Statement#%s.", item.UUID))
+		}
+
+		return models.StatementBatchResult{Status: http.StatusNoContent}
+	})
+}
+
+func StatementBatchesController() statementBatchesPrototype {
+	var controllerInstance statementBatchesPrototype
+	return controllerInstance
+}
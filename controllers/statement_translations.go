@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"strings"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/audit"
+	"jaha-api/db"
+	"jaha-api/env"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/utils"
+)
+
+type statementTranslationsPrototype struct{}
+
+/**
+ *	Lists translations of a statement, same visibility as statementsProtoype.Show.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (statementTranslationsPrototype) Index(ctx *gin.Context) {
+	var statement models.Statement
+	var queryError error
+
+	paramId := ctx.Param("uuid")
+	queryError = visibleStatements(ctx, db.GetConnection()).Preload("Translations").Where("`uuid` = ?", paramId).First(&statement).Error
+
+	if statement.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s not found.", paramId))
+		return
+	}
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	responders.Json().Success(ctx, statement.Translations)
+	return
+}
+
+/**
+ *	Creates a new translation of a statement.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (statementTranslationsPrototype) Create(ctx *gin.Context) {
+	var statement models.Statement
+	var payload models.StatementTranslationPayload
+	var translation models.StatementTranslation
+	var existing models.StatementTranslation
+	var createError error
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&statement)
+
+	if statement.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s not found.", paramId))
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	translation = models.StatementTranslation{
+		StatementId: statement.ID,
+		Language:    strings.ToLower(payload.Language),
+		Body:        payload.Body,
+	}
+
+	if !translation.Valid() {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": translation.GetErrors(),
+		})
+		return
+	}
+
+	if !env.IsSupportedLanguage(translation.Language) || translation.Language == env.GetDefaultLanguage() {
+		responders.Text().BadRequest(ctx, fmt.Sprintf("Could not create resource, language %s cannot be translated to.", translation.Language))
+		return
+	}
+
+	dbc.Unscoped().Where("`statement_id` = ? AND `language` = ?", statement.ID, translation.Language).First(&existing)
+
+	if existing.ID != 0 {
+		responders.Text().Conflict(ctx, fmt.Sprintf("Could not create resource, Statement#%s already has a %s translation.", paramId, translation.Language))
+		return
+	}
+
+	tx := dbc.Begin()
+	createError = tx.Create(&translation).Error
+
+	if createError == nil {
+		createError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_CREATE, audit.RESOURCE_TRANSLATION, statement.UUID, nil, translation)
+	}
+
+	if createError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, "Could not create resource, unknown error.")
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, translation)
+	return
+}
+
+/**
+ *	Updates existing translation of a statement.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (statementTranslationsPrototype) Update(ctx *gin.Context) {
+	var statement models.Statement
+	var translation models.StatementTranslation
+	var payload models.StatementTranslationPayload
+
+	paramId := ctx.Param("uuid")
+	paramLanguage := strings.ToLower(ctx.Param("language"))
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&statement)
+
+	if statement.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s not found.", paramId))
+		return
+	}
+
+	dbc.Where("`statement_id` = ? AND `language` = ?", statement.ID, paramLanguage).First(&translation)
+
+	if translation.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s has no %s translation.", paramId, paramLanguage))
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	// @NOTE Language is part of the resource path and cannot be changed.
+	payload.Language = ""
+
+	if payload == (models.StatementTranslationPayload{}) {
+		responders.Text().BadRequest(ctx, "Payload cannot be empty or malformed.")
+		return
+	}
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	before := translation
+
+	tx := dbc.Begin()
+	updateError := tx.Model(&translation).Updates(payload).Error
+
+	if updateError == nil {
+		updateError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_UPDATE, audit.RESOURCE_TRANSLATION, statement.UUID, before, translation)
+	}
+
+	if updateError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not update Statement#%s %s translation.", paramId, paramLanguage))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, translation)
+	return
+}
+
+func StatementTranslationsController() statementTranslationsPrototype {
+	var controllerInstance statementTranslationsPrototype
+	return controllerInstance
+}
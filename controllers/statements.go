@@ -9,12 +9,17 @@ import (
 	// 3rd party packages
 	"github.com/gin-gonic/gin"
 	"github.com/imdario/mergo"
+	"github.com/jinzhu/gorm"
 	"gopkg.in/guregu/null.v3"
 
 	// Local packages
+	"jaha-api/audit"
 	"jaha-api/db"
+	"jaha-api/env"
+	"jaha-api/identity"
 	"jaha-api/models"
 	"jaha-api/responders"
+	"jaha-api/revisions"
 	"jaha-api/scopes"
 	"jaha-api/utils"
 )
@@ -22,7 +27,122 @@ import (
 type statementsProtoype struct{}
 
 /**
- *	Lists published resources.
+ *	Returns language fallback chain from "lang" query parameter and Accept-Language header.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return []string
+ */
+func requestLanguages(ctx *gin.Context) []string {
+	preferredLanguages := []string{ctx.Query("lang")}
+	preferredLanguages = append(preferredLanguages, utils.ParseAcceptLanguage(ctx.Request.Header.Get("Accept-Language"))...)
+
+	return utils.LanguageFallbacks(preferredLanguages, env.GetDefaultLanguage())
+}
+
+/**
+ *	Finds statements similar to body, using full-text search for candidates and utils.Similarity for scoring.
+ *
+ *	@param dbc *gorm.DB - Connection or transaction to query with.
+ *	@param body string - Statement body to compare with.
+ *	@param excludeId int - Statement ID to leave out, 0 to compare with all statements.
+ *
+ *	@return models.StatementDuplicates, error
+ */
+func findSimilarStatements(dbc *gorm.DB, body string, excludeId int) (models.StatementDuplicates, error) {
+	var candidates models.Statements
+	var duplicates models.StatementDuplicates
+
+	query := dbc.Unscoped().Preload("Category").Where("MATCH(`statement`.`body`) AGAINST (? IN NATURAL LANGUAGE MODE)", body)
+
+	if excludeId != 0 {
+		query = query.Where("`statement`.`id` != ?", excludeId)
+	}
+
+	queryError := query.Limit(models.STATEMENT_DUPLICATE_CANDIDATES).Find(&candidates).Error
+
+	if queryError != nil {
+		return duplicates, queryError
+	}
+
+	for _, candidate := range candidates {
+		similarity := utils.Similarity(body, candidate.Body)
+
+		if similarity >= models.STATEMENT_DUPLICATE_THRESHOLD {
+			duplicates = append(duplicates, models.StatementDuplicate{
+				Statement:  candidate,
+				Similarity: similarity,
+			})
+		}
+	}
+
+	return duplicates, nil
+}
+
+/**
+ *	Checks statement body for near-duplicates, sends response and returns false if request cannot continue.
+ *	@NOTE Moderators may pass "force=true" to save near-duplicates anyway.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param body string - Statement body to check.
+ *	@param excludeId int - Statement ID to leave out, 0 when creating.
+ *
+ *	@return bool
+ */
+func checkSimilarStatements(ctx *gin.Context, body string, excludeId int) bool {
+	if ctx.Query("force") == "true" {
+		user := identity.GetUser(ctx)
+
+		if !user.IsModerator() {
+			responders.Text().Forbidden(ctx, "Only moderators can force near-duplicate statements.")
+			return false
+		}
+
+		return true
+	}
+
+	duplicates, queryError := findSimilarStatements(db.GetConnection(), body, excludeId)
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return false
+	}
+
+	if len(duplicates) > 0 {
+		responders.Json().Conflict(ctx, responders.Response{
+			"error":      "Statement is too similar to existing statements, see duplicates",
+			"duplicates": duplicates,
+		})
+		return false
+	}
+
+	return true
+}
+
+/**
+ *	Limits statement query to statements current user may see, unpublished statements are only shown to moderators and their owners.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param dbc *gorm.DB - Statement query.
+ *
+ *	@return *gorm.DB
+ */
+func visibleStatements(ctx *gin.Context, dbc *gorm.DB) *gorm.DB {
+	user := identity.GetUser(ctx)
+
+	if user.IsModerator() {
+		return dbc
+	}
+
+	if user.ID != 0 {
+		return dbc.Where("`statement`.`status` = ? OR `statement`.`user_id` = ?", models.STATEMENT_STATUS_PUBLISHED, user.ID)
+	}
+
+	return dbc.Scopes(scopes.Statement().Published)
+}
+
+/**
+ *	Lists published resources, moderators may list deleted resources with "trashed" param, see filterTrashed.
  *
  *	@param ctx gin.Context - Gin context pointer.
  *
@@ -39,8 +159,28 @@ func (statementsProtoype) Index(ctx *gin.Context) {
 	paramPage, _ := strconv.Atoi(utils.Pick(params.Get("page"), "1"))
 	paramOrderBy := utils.Pick(params.Get("orderBy"), "createdAt:asc")
 	paramScope := params.Get("scope")
+	paramSearch := strings.TrimSpace(params.Get("q"))
 
-	dbc := db.GetConnection()
+	dbc, paramTrashed, trashedValid := filterTrashed(ctx, db.GetConnection(), scopes.Statement().Deleted)
+
+	if !trashedValid {
+		return
+	}
+
+	// @NOTE Only published statements are public, moderators see deleted statements regardless of status.
+	switch paramTrashed {
+	case TRASHED_ONLY:
+		break
+	case TRASHED_WITH:
+		dbc = dbc.Where("(`statement`.`status` = ? OR `statement`.`deleted_at` IS NOT NULL)", models.STATEMENT_STATUS_PUBLISHED)
+		break
+	default:
+		dbc = dbc.Scopes(scopes.Statement().Published)
+	}
+
+	if maxLevel := requestMaxLevel(ctx); maxLevel != 0 {
+		dbc = dbc.Scopes(scopes.Statement().MaxLevel(maxLevel))
+	}
 
 	dbc.Model(&models.Statement{}).Count(&collectionCount)
 
@@ -49,8 +189,13 @@ func (statementsProtoype) Index(ctx *gin.Context) {
 	collection.Grab(nil, 1, collectionCount)
 	collection.SetPointer(paramPage)
 
-	query := dbc.Preload("Category")
+	query := dbc.Preload("Category").Preload("Translations")
 	applySortingFilters := true
+	orderByPopularity := false
+
+	if paramSearch != "" {
+		query = query.Scopes(scopes.Statement().Search(paramSearch))
+	}
 
 	scope := strings.Split(paramScope, ":")
 
@@ -78,6 +223,9 @@ func (statementsProtoype) Index(ctx *gin.Context) {
 
 			collection.Grab(statements, paramPage, len(statements))
 			break
+		case "popular":
+			orderByPopularity = true
+			break
 		case "category":
 			if scopeValue != "" {
 				query = query.Joins("LEFT JOIN `category` ON `category`.id = `statement`.category_id").Where("`category`.uuid = ?", scopeValue)
@@ -87,14 +235,23 @@ func (statementsProtoype) Index(ctx *gin.Context) {
 	}
 
 	if applySortingFilters {
-		// Set orderBy conditions
-		// @TODO Move MapOrderByConditions to Collection.
-		orderByConditions := utils.MapOrderByConditions(paramOrderBy)
-		query = utils.FilterOrderByConditions(query, models.Statement{}, orderByConditions)
+		query.Model(&models.Statement{}).Count(&collectionCount)
+
+		// @NOTE Search results are ranked by relevance unless orderBy is set explicitly.
+		if orderByPopularity && params.Get("orderBy") == "" {
+			query = query.Scopes(scopes.Statement().Popular)
+		} else if paramSearch != "" && params.Get("orderBy") == "" {
+			query = query.Order("`relevance` DESC")
+		} else {
+			// Set orderBy conditions
+			// @TODO Move MapOrderByConditions to Collection.
+			orderByConditions := utils.MapOrderByConditions(paramOrderBy)
+			query = utils.FilterOrderByConditions(query, models.Statement{}, orderByConditions)
+		}
 
 		queryError = query.Limit(collection.Limit).Offset(collection.GetOffset()).Find(&statements).Error
 
-		collection.Grab(statements, paramPage, len(statements))
+		collection.Grab(statements, paramPage, collectionCount)
 	}
 
 	if queryError != nil {
@@ -102,12 +259,26 @@ func (statementsProtoype) Index(ctx *gin.Context) {
 		return
 	}
 
+	languages := requestLanguages(ctx)
+
+	for index := range statements {
+		statements[index].Localize(languages, env.GetDefaultLanguage())
+
+		// @NOTE Highlight the localized body that is returned, highlight is HTML escaped and safe to render.
+		if paramSearch != "" {
+			statements[index].Highlight = utils.Highlight(statements[index].Body, utils.SearchTerms(paramSearch), "<mark>", "</mark>")
+		}
+	}
+
+	collection.SetRecords(statements)
+
+	ctx.Header("Vary", "Accept-Language")
 	responders.Json().Success(ctx, collection)
 	return
 }
 
 /**
- *	Retrieves published resource.
+ *	Retrieves published resource, unpublished statements are not found unless current user may see them, see visibleStatements.
  *
  *	@param ctx gin.Context - Gin context pointer.
  *
@@ -118,7 +289,7 @@ func (statementsProtoype) Show(ctx *gin.Context) {
 	var queryError error
 
 	paramId := ctx.Param("uuid")
-	queryError = db.GetConnection().Preload("Category").Where("`uuid` = ?", paramId).First(&statement).Error
+	queryError = visibleStatements(ctx, db.GetConnection()).Preload("Category").Preload("Translations").Where("`uuid` = ?", paramId).First(&statement).Error
 
 	if statement.ID == 0 {
 		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s not found.", paramId))
@@ -130,6 +301,10 @@ func (statementsProtoype) Show(ctx *gin.Context) {
 		return
 	}
 
+	statement.Localize(requestLanguages(ctx), env.GetDefaultLanguage())
+
+	ctx.Header("Vary", "Accept-Language")
+	ctx.Header("Content-Language", statement.Language)
 	responders.Json().Success(ctx, statement)
 	return
 }
@@ -149,6 +324,16 @@ func (statementsProtoype) Create(ctx *gin.Context) {
 
 	ctx.BindJSON(&payload)
 
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
 	dbc := db.GetConnection()
 
 	dbc.Unscoped().Where("`body` = ?", payload.Body).First(&existing)
@@ -163,6 +348,10 @@ func (statementsProtoype) Create(ctx *gin.Context) {
 		return
 	}
 
+	if !checkSimilarStatements(ctx, payload.Body, 0) {
+		return
+	}
+
 	category := models.Category{}
 	categoryError := dbc.Model(&models.Category{}).Where("`uuid` = ?", payload.Category).First(&category).Error
 
@@ -174,16 +363,37 @@ func (statementsProtoype) Create(ctx *gin.Context) {
 	mergo.Merge(&statement, models.Statement{
 		UUID:     utils.RandomString(8),
 		Body:     payload.Body,
+		Status:   models.STATEMENT_STATUS_DRAFT,
 		Category: category,
 	})
 
-	createError = dbc.Create(&statement).Error
+	if user := identity.GetUser(ctx); user.ID != 0 {
+		statement.UserId = null.IntFrom(int64(user.ID))
+	}
+
+	if payload.Level != 0 {
+		statement.Level = null.IntFrom(int64(payload.Level))
+	}
+
+	tx := dbc.Begin()
+	createError = tx.Create(&statement).Error
+
+	if createError == nil {
+		createError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_CREATE, audit.RESOURCE_STATEMENT, statement.UUID, nil, statement)
+	}
+
+	if createError == nil {
+		_, createError = revisions.Record(tx, identity.GetUser(ctx), audit.RESOURCE_STATEMENT, statement.ID, nil, statement.Snapshot(), 0)
+	}
 
 	if createError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, "Could not create resource, unknown error.")
 		return
 	}
 
+	tx.Commit()
+
 	responders.Json().Success(ctx, statement)
 	return
 }
@@ -197,13 +407,14 @@ func (statementsProtoype) Create(ctx *gin.Context) {
  */
 func (statementsProtoype) Update(ctx *gin.Context) {
 	var statement models.Statement
+	var category models.Category
 	var payload models.StatementPayload
 	var queryError error
 
 	paramId := ctx.Param("uuid")
 
 	dbc := db.GetConnection()
-	queryError = dbc.Unscoped().Where("`uuid` = ?", paramId).First(&statement).Error
+	queryError = dbc.Unscoped().Preload("Category").Where("`uuid` = ?", paramId).First(&statement).Error
 
 	if statement.ID == 0 {
 		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s not found.", paramId))
@@ -232,13 +443,60 @@ func (statementsProtoype) Update(ctx *gin.Context) {
 		return
 	}
 
-	updateError := dbc.Model(&statement).Unscoped().Updates(payload).Error
+	if payload.Body != "" && !checkSimilarStatements(ctx, payload.Body, statement.ID) {
+		return
+	}
+
+	changes := map[string]interface{}{}
+
+	if payload.Body != "" {
+		changes["body"] = payload.Body
+	}
+
+	if payload.Level != 0 {
+		changes["level"] = null.IntFrom(int64(payload.Level))
+	}
+
+	if payload.Category != "" {
+		dbc.Where("`uuid` = ?", payload.Category).First(&category)
+
+		if category.ID == 0 {
+			responders.Text().NotFound(ctx, fmt.Sprintf("Category#%s not found.", payload.Category))
+			return
+		}
+
+		changes["category_id"] = category.ID
+	}
+
+	before := statement
+
+	tx := dbc.Begin()
+	updateError := tx.Set("gorm:save_associations", false).Model(&statement).Unscoped().Updates(changes).Error
+
+	if category.ID != 0 {
+		statement.Category = category
+	}
+
+	if updateError == nil {
+		updateError = resetStatementReview(tx, identity.GetUser(ctx), &statement)
+	}
+
+	if updateError == nil {
+		updateError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_UPDATE, audit.RESOURCE_STATEMENT, statement.UUID, before, statement)
+	}
+
+	if updateError == nil {
+		_, updateError = revisions.Record(tx, identity.GetUser(ctx), audit.RESOURCE_STATEMENT, statement.ID, before.Snapshot(), statement.Snapshot(), 0)
+	}
 
 	if updateError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, fmt.Sprintf("Could not update Statement#%s.", paramId))
 		return
 	}
 
+	tx.Commit()
+
 	responders.Json().Success(ctx, statement)
 	return
 }
@@ -270,13 +528,21 @@ func (statementsProtoype) Destroy(ctx *gin.Context) {
 		return
 	}
 
-	destroyError = dbc.Delete(&statement).Error
+	tx := dbc.Begin()
+	destroyError = tx.Delete(&statement).Error
+
+	if destroyError == nil {
+		destroyError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_DESTROY, audit.RESOURCE_STATEMENT, statement.UUID, statement, nil)
+	}
 
 	if destroyError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, fmt.Sprintf("Could not destroy resource Statement#%s.", paramId))
 		return
 	}
 
+	tx.Commit()
+
 	responders.NoContent(ctx)
 	return
 }
@@ -290,6 +556,7 @@ func (statementsProtoype) Destroy(ctx *gin.Context) {
  */
 func (statementsProtoype) Restore(ctx *gin.Context) {
 	var statement models.Statement
+	var category models.Category
 	var queryError error
 	var restoreError error
 
@@ -313,17 +580,189 @@ func (statementsProtoype) Restore(ctx *gin.Context) {
 		return
 	}
 
-	restoreError = dbc.Model(&statement).Unscoped().Update("deleted_at", "NULL").Error
+	dbc.Unscoped().Where("`id` = ?", statement.CategoryId).First(&category)
+
+	if category.DeletedAt.Valid {
+		responders.Text().Conflict(ctx, fmt.Sprintf("Statement#%s belongs to destroyed Category#%s, restore the category first.", paramId, category.UUID))
+		return
+	}
+
+	before := statement
+
+	tx := dbc.Begin()
+	restoreError = tx.Model(&statement).Unscoped().Updates(map[string]interface{}{"deleted_at": nil, "deleted_with_category": false}).Error
+
+	if restoreError == nil {
+		restoreError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_RESTORE, audit.RESOURCE_STATEMENT, statement.UUID, before, statement)
+	}
 
 	if restoreError != nil {
-		responders.Text().ServerError(ctx, queryError.Error())
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not restore resource Statement#%s.", paramId))
 		return
 	}
 
+	tx.Commit()
+
 	responders.Json().Success(ctx, statement)
 	return
 }
 
+/**
+ *	Moves statement to another status and records the transition, decisions require a moderator and a reason.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (statementsProtoype) Transition(ctx *gin.Context) {
+	var statement models.Statement
+	var payload models.StatementTransitionPayload
+	var moderation models.StatementModeration
+	var transitionError error
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Preload("Category").Where("`uuid` = ?", paramId).First(&statement)
+
+	if statement.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s not found.", paramId))
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	user := identity.GetUser(ctx)
+
+	if !user.IsModerator() && !statement.CanOwnerTransition(payload.Status) {
+		responders.Text().Forbidden(ctx, fmt.Sprintf("Only moderators can change Statement#%s from %s to %s.", paramId, statement.Status, payload.Status))
+		return
+	}
+
+	if models.IsStatementDecision(payload.Status) && strings.TrimSpace(payload.Reason) == "" {
+		responders.Text().BadRequest(ctx, "Moderation decisions require a reason.")
+		return
+	}
+
+	if !statement.CanTransition(payload.Status) {
+		responders.Text().Conflict(ctx, fmt.Sprintf("Statement#%s cannot change from %s to %s.", paramId, statement.Status, payload.Status))
+		return
+	}
+
+	moderation = models.StatementModeration{
+		StatementId: statement.ID,
+		FromStatus:  statement.Status,
+		ToStatus:    payload.Status,
+		Reason:      strings.TrimSpace(payload.Reason),
+	}
+
+	if user.ID != 0 {
+		moderation.UserId = null.IntFrom(int64(user.ID))
+	}
+
+	before := statement
+
+	tx := dbc.Begin()
+	transitionError = tx.Set("gorm:save_associations", false).Model(&statement).Update("status", payload.Status).Error
+
+	if transitionError == nil {
+		transitionError = tx.Create(&moderation).Error
+	}
+
+	if transitionError == nil {
+		transitionError = audit.Record(tx, user, audit.ACTION_TRANSITION, audit.RESOURCE_STATEMENT, statement.UUID, before, statement)
+	}
+
+	if transitionError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not change status of Statement#%s.", paramId))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, statement)
+	return
+}
+
+/**
+ *	Moves edited published statement back to pending, edited text has to be reviewed again.
+ *
+ *	@param tx *gorm.DB - Transaction of the edit.
+ *	@param user models.User - User who edited the statement.
+ *	@param statement *models.Statement - Edited statement, status is updated in place.
+ *
+ *	@return error
+ */
+func resetStatementReview(tx *gorm.DB, user models.User, statement *models.Statement) error {
+	if statement.Status != models.STATEMENT_STATUS_PUBLISHED {
+		return nil
+	}
+
+	moderation := models.StatementModeration{
+		StatementId: statement.ID,
+		FromStatus:  statement.Status,
+		ToStatus:    models.STATEMENT_STATUS_PENDING,
+		Reason:      models.STATEMENT_EDIT_REASON,
+	}
+
+	if user.ID != 0 {
+		moderation.UserId = null.IntFrom(int64(user.ID))
+	}
+
+	updateError := tx.Set("gorm:save_associations", false).Model(statement).Unscoped().Update("status", models.STATEMENT_STATUS_PENDING).Error
+
+	if updateError != nil {
+		return updateError
+	}
+
+	return tx.Create(&moderation).Error
+}
+
+/**
+ *	Lists status transitions of a statement, newest first.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (statementsProtoype) Transitions(ctx *gin.Context) {
+	var statement models.Statement
+	var moderations models.StatementModerations
+	var queryError error
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Unscoped().Where("`uuid` = ?", paramId).First(&statement)
+
+	if statement.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s not found.", paramId))
+		return
+	}
+
+	queryError = dbc.Where("`statement_id` = ?", statement.ID).Order("`created_at` DESC, `id` DESC").Find(&moderations).Error
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	responders.Json().Success(ctx, moderations)
+	return
+}
+
 func StatementsController() statementsProtoype {
 	var controllerInstance statementsProtoype
 	return controllerInstance
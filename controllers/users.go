@@ -3,7 +3,9 @@ package controllers
 import (
 	// Native packages
 	"fmt"
+	"log"
 	"strconv"
+	"time"
 
 	// 3rd party packages
 	"github.com/gin-gonic/gin"
@@ -11,16 +13,20 @@ import (
 	"gopkg.in/guregu/null.v3"
 
 	// Local packages
+	"jaha-api/audit"
 	"jaha-api/db"
+	"jaha-api/identity"
 	"jaha-api/models"
+	"jaha-api/purge"
 	"jaha-api/responders"
+	"jaha-api/scopes"
 	"jaha-api/utils"
 )
 
 type usersPrototype struct{}
 
 /**
- *	Lists published resources.
+ *	Lists published resources, deleted resources are listed with "trashed" param, see filterTrashed.
  *
  *	@param ctx gin.Context - Gin context pointer.
  *
@@ -38,6 +44,17 @@ func (usersPrototype) Index(ctx *gin.Context) {
 
 	dbc := db.GetConnection()
 
+	// @NOTE Lists only users with a currently locked out email.
+	if params.Get("locked") == "true" {
+		dbc = dbc.Where("`email` IN (SELECT `subject` FROM `login_lockout` WHERE `subject_type` = ? AND `locked_until` > NOW())", models.LOGIN_SUBJECT_EMAIL)
+	}
+
+	dbc, _, trashedValid := filterTrashed(ctx, dbc, scopes.User().Deleted)
+
+	if !trashedValid {
+		return
+	}
+
 	dbc.Model(&models.User{}).Count(&collectionCount)
 
 	collection = models.Collection{}
@@ -114,6 +131,11 @@ func (usersPrototype) Create(ctx *gin.Context) {
 		return
 	}
 
+	// @NOTE Email is verified through a mailed token only, role and suspension are changed by administrators only.
+	user.VerifiedAt = null.Time{}
+	user.SuspendedAt = null.Time{}
+	user.Role = 0
+
 	mergo.Merge(&user, models.User{
 		Role:    1,
 		UUID:    utils.RandomString(8),
@@ -132,13 +154,25 @@ func (usersPrototype) Create(ctx *gin.Context) {
 		return
 	}
 
-	createError = dbc.Create(&user).Error
+	tx := dbc.Begin()
+	createError = tx.Create(&user).Error
+
+	if createError == nil {
+		createError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_CREATE, audit.RESOURCE_USER, user.UUID, nil, user)
+	}
 
 	if createError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, "Could not create resource, unknown error.")
 		return
 	}
 
+	tx.Commit()
+
+	if sendError := sendUserToken(user, models.USER_TOKEN_PURPOSE_VERIFY); sendError != nil {
+		log.Printf("Could not send verification mail to User#%s: %s", user.UUID, sendError)
+	}
+
 	responders.Json().Success(ctx, user)
 	return
 }
@@ -182,9 +216,6 @@ func (usersPrototype) Update(ctx *gin.Context) {
 			"error": "Passwords must match.",
 		})
 		return
-	} else {
-		payload.Password = utils.PasswordCreate(payload.Password)
-		payload.PasswordConfirm = payload.Password
 	}
 
 	validationError, validationErrors := utils.Validate(payload)
@@ -197,13 +228,30 @@ func (usersPrototype) Update(ctx *gin.Context) {
 		return
 	}
 
-	updateError := dbc.Model(&user).Unscoped().Updates(payload).Error
+	// @NOTE Empty password is left out of the update, hashing it would replace the stored password.
+	if payload.Password != "" {
+		payload.Password = utils.PasswordCreate(payload.Password)
+	}
+
+	payload.PasswordConfirm = ""
+
+	before := user
+
+	tx := dbc.Begin()
+	updateError := tx.Model(&user).Unscoped().Updates(payload).Error
+
+	if updateError == nil {
+		updateError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_UPDATE, audit.RESOURCE_USER, user.UUID, before, user)
+	}
 
 	if updateError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, fmt.Sprintf("Could not update User#%s.", paramId))
 		return
 	}
 
+	tx.Commit()
+
 	responders.Json().Success(ctx, user)
 	return
 }
@@ -235,13 +283,21 @@ func (usersPrototype) Destroy(ctx *gin.Context) {
 		return
 	}
 
-	destroyError = dbc.Delete(&user).Error
+	tx := dbc.Begin()
+	destroyError = tx.Delete(&user).Error
+
+	if destroyError == nil {
+		destroyError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_DESTROY, audit.RESOURCE_USER, user.UUID, user, nil)
+	}
 
 	if destroyError != nil {
+		tx.Rollback()
 		responders.Text().ServerError(ctx, fmt.Sprintf("Could not destroy resource User#%s.", paramId))
 		return
 	}
 
+	tx.Commit()
+
 	responders.NoContent(ctx)
 	return
 }
@@ -278,17 +334,370 @@ func (usersPrototype) Restore(ctx *gin.Context) {
 		return
 	}
 
-	restoreError = dbc.Model(&user).Unscoped().Update("deleted_at", "NULL").Error
+	before := user
+
+	tx := dbc.Begin()
+	restoreError = tx.Model(&user).Unscoped().Update("deleted_at", nil).Error
+
+	if restoreError == nil {
+		restoreError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_RESTORE, audit.RESOURCE_USER, user.UUID, before, user)
+	}
 
 	if restoreError != nil {
-		responders.Text().ServerError(ctx, queryError.Error())
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not restore resource User#%s.", paramId))
 		return
 	}
 
+	tx.Commit()
+
 	responders.Json().Success(ctx, user)
 	return
 }
 
+/**
+ *	Rotates user auth key, every access token and refresh token issued to user stops working.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (usersPrototype) RotateAuthKey(ctx *gin.Context) {
+	var user models.User
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	rotateError := identity.RotateAuthKey(dbc, &user)
+
+	if rotateError != nil {
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not rotate auth key of User#%s.", paramId))
+		return
+	}
+
+	responders.NoContent(ctx)
+	return
+}
+
+/**
+ *	Shows failed login attempts and lockout state of user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (usersPrototype) Lockout(ctx *gin.Context) {
+	var user models.User
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Unscoped().Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	lockout := identity.FindLoginLockout(dbc, user.Email)
+
+	if lockout.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s has no failed login attempts.", paramId))
+		return
+	}
+
+	responders.Json().Success(ctx, lockout)
+	return
+}
+
+/**
+ *	Clears failed login attempts and lockout of user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (usersPrototype) Unlock(ctx *gin.Context) {
+	var user models.User
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Unscoped().Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	if identity.ClearLoginLockout(dbc, user.Email) != nil {
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not clear lockout of User#%s.", paramId))
+		return
+	}
+
+	responders.NoContent(ctx)
+	return
+}
+
+/**
+ *	Changes role of user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (usersPrototype) Role(ctx *gin.Context) {
+	var user models.User
+	var payload models.UserRolePayload
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	if payload.Role == user.Role {
+		responders.Text().Conflict(ctx, fmt.Sprintf("User#%s already has role %d.", paramId, payload.Role))
+		return
+	}
+
+	before := user
+	user.Role = payload.Role
+
+	tx := db.GetConnection().Begin()
+	updateError := tx.Model(&user).UpdateColumn("role", user.Role).Error
+
+	if updateError == nil {
+		updateError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_ROLE, audit.RESOURCE_USER, user.UUID, before, user)
+	}
+
+	if updateError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not change role of User#%s.", paramId))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, user)
+	return
+}
+
+/**
+ *	Suspends user, suspended users keep their data but cannot authenticate.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (usersPrototype) Suspend(ctx *gin.Context) {
+	var user models.User
+	var payload models.UserSuspensionPayload
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	if user.IsSuspended() {
+		responders.Text().Conflict(ctx, fmt.Sprintf("User#%s already suspended.", paramId))
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	before := user
+	user.SuspendedAt = null.TimeFrom(time.Now())
+	user.SuspensionReason = null.StringFrom(payload.Reason)
+
+	tx := db.GetConnection().Begin()
+	suspendError := tx.Model(&user).UpdateColumns(map[string]interface{}{
+		"suspended_at":      user.SuspendedAt,
+		"suspension_reason": user.SuspensionReason,
+	}).Error
+
+	if suspendError == nil {
+		suspendError = identity.RevokeRefreshTokens(tx, user.ID)
+	}
+
+	if suspendError == nil {
+		suspendError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_SUSPEND, audit.RESOURCE_USER, user.UUID, before, user)
+	}
+
+	if suspendError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not suspend User#%s.", paramId))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, user)
+	return
+}
+
+/**
+ *	Lifts suspension of user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (usersPrototype) Unsuspend(ctx *gin.Context) {
+	var user models.User
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	if !user.IsSuspended() {
+		responders.Text().Conflict(ctx, fmt.Sprintf("User#%s is not suspended.", paramId))
+		return
+	}
+
+	before := user
+	user.SuspendedAt = null.Time{}
+	user.SuspensionReason = null.String{}
+
+	tx := db.GetConnection().Begin()
+	unsuspendError := tx.Model(&user).UpdateColumns(map[string]interface{}{
+		"suspended_at":      nil,
+		"suspension_reason": nil,
+	}).Error
+
+	if unsuspendError == nil {
+		unsuspendError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_UNSUSPEND, audit.RESOURCE_USER, user.UUID, before, user)
+	}
+
+	if unsuspendError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not unsuspend User#%s.", paramId))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, user)
+	return
+}
+
+/**
+ *	Permanently removes a soft deleted user and data that cannot exist without it, see purge.User.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (usersPrototype) Purge(ctx *gin.Context) {
+	var user models.User
+	var purgeError error
+
+	paramId := ctx.Param("uuid")
+
+	dbc := db.GetConnection()
+	dbc.Unscoped().Where("`uuid` = ?", paramId).First(&user)
+
+	if user.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("User#%s not found.", paramId))
+		return
+	}
+
+	if !user.DeletedAt.Valid {
+		responders.Text().Conflict(ctx, fmt.Sprintf("User#%s must be deleted before it is purged.", paramId))
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+
+	purgeError = purge.User(tx, user.ID)
+
+	// @NOTE Only the UUID is recorded, audit log outlives the purged personal data.
+	if purgeError == nil {
+		purgeError = audit.Record(tx, identity.GetUser(ctx), audit.ACTION_PURGE, audit.RESOURCE_USER, user.UUID, nil, nil)
+	}
+
+	if purgeError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not purge User#%s.", paramId))
+		return
+	}
+
+	tx.Commit()
+
+	responders.NoContent(ctx)
+	return
+}
+
+/**
+ *	Lists administrative changes of user from audit log, newest first. Purged users are listed too.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (usersPrototype) Actions(ctx *gin.Context) {
+	var entries models.AuditEntries
+
+	paramId := ctx.Param("uuid")
+
+	queryError := db.GetConnection().Where(
+		"`resource_type` = ? AND `resource_uuid` = ? AND `action` IN (?)",
+		audit.RESOURCE_USER, paramId, []string{audit.ACTION_ROLE, audit.ACTION_SUSPEND, audit.ACTION_UNSUSPEND, audit.ACTION_PURGE},
+	).Order("`id` DESC").Find(&entries).Error
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	responders.Json().Success(ctx, loadAuditActors(entries))
+	return
+}
+
 func UsersController() usersPrototype {
 	var controllerInstance usersPrototype
 	return controllerInstance
@@ -0,0 +1,365 @@
+package controllers
+
+import (
+	// Native packages
+	"fmt"
+	"strconv"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/identity"
+	"jaha-api/models"
+	"jaha-api/responders"
+	"jaha-api/scopes"
+	"jaha-api/utils"
+)
+
+type votesPrototype struct{}
+
+/**
+ *	Finds statement and authenticated user for a vote request, sends response and returns false if request cannot continue.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *	@param statement *models.Statement - Statement to load.
+ *	@param user *models.User - User to load.
+ *
+ *	@return bool
+ */
+func findVoteTarget(ctx *gin.Context, statement *models.Statement, user *models.User) bool {
+	paramId := ctx.Param("uuid")
+
+	*user = identity.GetUser(ctx)
+
+	if user.ID == 0 {
+		responders.Text().Unauthorized(ctx, "Voting requires an authenticated user.")
+		return false
+	}
+
+	db.GetConnection().Scopes(scopes.Statement().Published).Where("`uuid` = ?", paramId).First(statement)
+
+	if statement.ID == 0 {
+		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s not found.", paramId))
+		return false
+	}
+
+	return true
+}
+
+/**
+ *	Locks statement row and reloads its counters inside transaction.
+ *	@NOTE Locking a missing vote or favourite row takes no lock, concurrent first votes are serialized on the statement instead.
+ *
+ *	@param tx *gorm.DB - Transaction to lock in.
+ *	@param statement *models.Statement - Statement to lock, counters are reloaded in place.
+ *
+ *	@return error
+ */
+func lockVoteTarget(tx *gorm.DB, statement *models.Statement) error {
+	return tx.Set("gorm:query_option", "FOR UPDATE").Where("`id` = ?", statement.ID).First(statement).Error
+}
+
+/**
+ *	Adds vote count deltas to statement counters.
+ *
+ *	@param tx *gorm.DB - Transaction to update in.
+ *	@param statement *models.Statement - Statement to update, counters are updated in place.
+ *	@param previousValue int - Previous vote value, 0 if there was no vote.
+ *	@param newValue int - New vote value, 0 if vote is removed.
+ *
+ *	@return error
+ */
+func updateVoteCounts(tx *gorm.DB, statement *models.Statement, previousValue int, newValue int) error {
+	upvoteDelta, downvoteDelta := models.VoteCountDeltas(previousValue, newValue)
+
+	statement.Upvotes += upvoteDelta
+	statement.Downvotes += downvoteDelta
+
+	return tx.Model(&models.Statement{}).Where("`id` = ?", statement.ID).UpdateColumns(map[string]interface{}{
+		"upvote_count":   gorm.Expr("`upvote_count` + ?", upvoteDelta),
+		"downvote_count": gorm.Expr("`downvote_count` + ?", downvoteDelta),
+	}).Error
+}
+
+/**
+ *	Casts or replaces authenticated user vote on a statement.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (votesPrototype) Vote(ctx *gin.Context) {
+	var statement models.Statement
+	var user models.User
+	var payload models.StatementVotePayload
+	var vote models.StatementVote
+	var voteError error
+
+	if !findVoteTarget(ctx, &statement, &user) {
+		return
+	}
+
+	ctx.BindJSON(&payload)
+
+	validationError, validationErrors := utils.Validate(payload)
+
+	if validationError != nil {
+		responders.Json().BadRequest(ctx, responders.Response{
+			"error":  "Resource validation failed, see issues",
+			"issues": validationErrors,
+		})
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+
+	if lockError := lockVoteTarget(tx, &statement); lockError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not vote on Statement#%s.", statement.UUID))
+		return
+	}
+
+	tx.Set("gorm:query_option", "FOR UPDATE").Where("`statement_id` = ? AND `user_id` = ?", statement.ID, user.ID).First(&vote)
+
+	previousValue := vote.Value
+
+	if vote.ID == 0 {
+		vote = models.StatementVote{
+			StatementId: statement.ID,
+			UserId:      user.ID,
+			Value:       payload.Value,
+		}
+		voteError = tx.Create(&vote).Error
+	} else if vote.Value != payload.Value {
+		vote.Value = payload.Value
+		voteError = tx.Model(&vote).Update("value", payload.Value).Error
+	}
+
+	if voteError == nil {
+		voteError = updateVoteCounts(tx, &statement, previousValue, payload.Value)
+	}
+
+	if voteError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not vote on Statement#%s.", statement.UUID))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, statement)
+	return
+}
+
+/**
+ *	Removes authenticated user vote from a statement.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (votesPrototype) Unvote(ctx *gin.Context) {
+	var statement models.Statement
+	var user models.User
+	var vote models.StatementVote
+	var voteError error
+
+	if !findVoteTarget(ctx, &statement, &user) {
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+
+	if lockError := lockVoteTarget(tx, &statement); lockError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not remove vote from Statement#%s.", statement.UUID))
+		return
+	}
+
+	tx.Set("gorm:query_option", "FOR UPDATE").Where("`statement_id` = ? AND `user_id` = ?", statement.ID, user.ID).First(&vote)
+
+	if vote.ID == 0 {
+		tx.Rollback()
+		responders.Text().NotFound(ctx, fmt.Sprintf("No vote on Statement#%s.", statement.UUID))
+		return
+	}
+
+	voteError = tx.Delete(&vote).Error
+
+	if voteError == nil {
+		voteError = updateVoteCounts(tx, &statement, vote.Value, 0)
+	}
+
+	if voteError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not remove vote from Statement#%s.", statement.UUID))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, statement)
+	return
+}
+
+/**
+ *	Marks statement as favourite of authenticated user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (votesPrototype) Favourite(ctx *gin.Context) {
+	var statement models.Statement
+	var user models.User
+	var favourite models.StatementFavourite
+	var favouriteError error
+
+	if !findVoteTarget(ctx, &statement, &user) {
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+
+	if lockError := lockVoteTarget(tx, &statement); lockError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not favourite Statement#%s.", statement.UUID))
+		return
+	}
+
+	tx.Set("gorm:query_option", "FOR UPDATE").Where("`statement_id` = ? AND `user_id` = ?", statement.ID, user.ID).First(&favourite)
+
+	if favourite.ID != 0 {
+		tx.Rollback()
+		responders.Json().Success(ctx, statement)
+		return
+	}
+
+	favourite = models.StatementFavourite{
+		StatementId: statement.ID,
+		UserId:      user.ID,
+	}
+
+	favouriteError = tx.Set("gorm:save_associations", false).Create(&favourite).Error
+
+	if favouriteError == nil {
+		statement.Favourites++
+		favouriteError = tx.Model(&models.Statement{}).Where("`id` = ?", statement.ID).UpdateColumn("favourite_count", gorm.Expr("`favourite_count` + 1")).Error
+	}
+
+	if favouriteError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not favourite Statement#%s.", statement.UUID))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, statement)
+	return
+}
+
+/**
+ *	Removes statement from favourites of authenticated user.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (votesPrototype) Unfavourite(ctx *gin.Context) {
+	var statement models.Statement
+	var user models.User
+	var favourite models.StatementFavourite
+	var favouriteError error
+
+	if !findVoteTarget(ctx, &statement, &user) {
+		return
+	}
+
+	tx := db.GetConnection().Begin()
+
+	if lockError := lockVoteTarget(tx, &statement); lockError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not remove Statement#%s from favourites.", statement.UUID))
+		return
+	}
+
+	tx.Set("gorm:query_option", "FOR UPDATE").Where("`statement_id` = ? AND `user_id` = ?", statement.ID, user.ID).First(&favourite)
+
+	if favourite.ID == 0 {
+		tx.Rollback()
+		responders.Text().NotFound(ctx, fmt.Sprintf("Statement#%s is not a favourite.", statement.UUID))
+		return
+	}
+
+	favouriteError = tx.Delete(&favourite).Error
+
+	if favouriteError == nil {
+		statement.Favourites--
+		favouriteError = tx.Model(&models.Statement{}).Where("`id` = ?", statement.ID).UpdateColumn("favourite_count", gorm.Expr("`favourite_count` - 1")).Error
+	}
+
+	if favouriteError != nil {
+		tx.Rollback()
+		responders.Text().ServerError(ctx, fmt.Sprintf("Could not remove Statement#%s from favourites.", statement.UUID))
+		return
+	}
+
+	tx.Commit()
+
+	responders.Json().Success(ctx, statement)
+	return
+}
+
+/**
+ *	Lists favourite statements of authenticated user, newest favourite first.
+ *
+ *	@param ctx gin.Context - Gin context pointer.
+ *
+ *	@return void
+ */
+func (votesPrototype) Favourites(ctx *gin.Context) {
+	var favourites models.StatementFavourites
+	var collection models.Collection
+	var collectionCount int
+	var queryError error
+
+	user := identity.GetUser(ctx)
+
+	if user.ID == 0 {
+		responders.Text().Unauthorized(ctx, "Favourites require an authenticated user.")
+		return
+	}
+
+	params := ctx.Request.URL.Query()
+	paramPage, _ := strconv.Atoi(utils.Pick(params.Get("page"), "1"))
+
+	dbc := db.GetConnection().Where("`user_id` = ?", user.ID)
+
+	dbc.Model(&models.StatementFavourite{}).Count(&collectionCount)
+
+	collection = models.Collection{}
+	collection.SetLimit(COLLECTION_DEFAULT_LIMIT)
+	collection.Grab(nil, 1, collectionCount)
+	collection.SetPointer(paramPage)
+
+	queryError = dbc.Preload("Statement").Preload("Statement.Category").Order("`created_at` DESC").Limit(collection.Limit).Offset(collection.GetOffset()).Find(&favourites).Error
+
+	if queryError != nil {
+		responders.Text().ServerError(ctx, queryError.Error())
+		return
+	}
+
+	collection.Grab(favourites, paramPage, collectionCount)
+
+	responders.Json().Success(ctx, collection)
+	return
+}
+
+func VotesController() votesPrototype {
+	var controllerInstance votesPrototype
+	return controllerInstance
+}
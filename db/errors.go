@@ -0,0 +1,22 @@
+package db
+
+import (
+	// 3rd party packages
+	"github.com/go-sql-driver/mysql"
+)
+
+// @NOTE MySQL error number for ER_DUP_ENTRY.
+const MYSQL_DUPLICATE_KEY = 1062
+
+/**
+ *	Returns true if query failed on a unique key, e.g. when a concurrent request inserted the same row first.
+ *
+ *	@param queryError error - Query error.
+ *
+ *	@return bool
+ */
+func IsDuplicateKeyError(queryError error) bool {
+	mysqlError, isMysqlError := queryError.(*mysql.MySQLError)
+
+	return isMysqlError && mysqlError.Number == MYSQL_DUPLICATE_KEY
+}
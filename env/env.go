@@ -3,6 +3,9 @@ package env
 import (
 	// Native packages
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	// Local packages
 	"jaha-api/utils"
@@ -11,6 +14,9 @@ import (
 const ENV_DEVELOPMENT = "development"
 const ENV_PRODUCTION = "production"
 
+const MAIL_SENDER_FILE = "file"
+const MAIL_SENDER_SMTP = "smtp"
+
 /**
  *	Returns MODE environment variable, defaults to ENV_DEVELOPMENT.
  *
@@ -88,6 +94,261 @@ func GetAppName() string {
 	return utils.Pick(os.Getenv("APP_NAME"), "jaha-api-app")
 }
 
+/**
+ *	Returns public URL of client app, used for links in outgoing mail.
+ *
+ *	@return string
+ */
+func GetAppUrl() string {
+	return strings.TrimRight(utils.Pick(os.Getenv("APP_URL"), "http://localhost:"+GetPort()), "/")
+}
+
+/**
+ *	Returns mail sender name, MAIL_SENDER_FILE or MAIL_SENDER_SMTP, defaults to MAIL_SENDER_FILE.
+ *
+ *	@return string
+ */
+func GetMailSender() string {
+	return utils.Pick(os.Getenv("MAIL_SENDER"), MAIL_SENDER_FILE)
+}
+
+/**
+ *	Returns directory file mail sender writes to, defaults to "tmp/mail".
+ *
+ *	@return string
+ */
+func GetMailDirectory() string {
+	return utils.Pick(os.Getenv("MAIL_DIR"), "tmp/mail")
+}
+
+/**
+ *	Returns sender address of outgoing mail.
+ *
+ *	@return string
+ */
+func GetMailFrom() string {
+	return utils.Pick(os.Getenv("MAIL_FROM"), "no-reply@localhost")
+}
+
+/**
+ *	Returns SMTP server address, defaults to "localhost:25".
+ *
+ *	@return string
+ */
+func GetSmtpAddress() string {
+	return utils.Pick(os.Getenv("SMTP_ADDR"), "localhost:25")
+}
+
+/**
+ *	Returns SMTP username, empty if server does not require authentication.
+ *
+ *	@return string
+ */
+func GetSmtpUsername() string {
+	return os.Getenv("SMTP_USER")
+}
+
+/**
+ *	Returns SMTP password.
+ *
+ *	@return string
+ */
+func GetSmtpPassword() string {
+	return os.Getenv("SMTP_PASSWORD")
+}
+
+/**
+ *	Returns OIDC provider name, stored with linked identities, defaults to "oidc".
+ *
+ *	@return string
+ */
+func GetOidcProviderName() string {
+	return utils.Pick(os.Getenv("OIDC_PROVIDER"), "oidc")
+}
+
+/**
+ *	Returns OIDC issuer URL, OIDC login is disabled if empty.
+ *
+ *	@return string
+ */
+func GetOidcIssuer() string {
+	return os.Getenv("OIDC_ISSUER")
+}
+
+/**
+ *	Returns OIDC client ID, OIDC login is disabled if empty.
+ *
+ *	@return string
+ */
+func GetOidcClientId() string {
+	return os.Getenv("OIDC_CLIENT_ID")
+}
+
+/**
+ *	Returns OIDC client secret, empty for public clients using PKCE only.
+ *
+ *	@return string
+ */
+func GetOidcClientSecret() string {
+	return os.Getenv("OIDC_CLIENT_SECRET")
+}
+
+/**
+ *	Returns OIDC redirect URL, defaults to callback endpoint on localhost.
+ *
+ *	@return string
+ */
+func GetOidcRedirectUrl() string {
+	return utils.Pick(os.Getenv("OIDC_REDIRECT_URL"), "http://localhost:"+GetPort()+"/v1/auth/oidc/callback")
+}
+
+/**
+ *	Returns requested OIDC scopes from space separated OIDC_SCOPES, defaults to "openid email profile".
+ *
+ *	@return []string
+ */
+func GetOidcScopes() []string {
+	return strings.Fields(utils.Pick(os.Getenv("OIDC_SCOPES"), "openid email profile"))
+}
+
+/**
+ *	Returns how long soft deleted rows are kept before they are purged, from RETENTION_DAYS, defaults to 30 days.
+ *	@NOTE 0 disables purging.
+ *
+ *	@return time.Duration
+ */
+func GetRetentionPeriod() time.Duration {
+	retentionDays, parseError := strconv.Atoi(utils.Pick(os.Getenv("RETENTION_DAYS"), "30"))
+
+	if parseError != nil || retentionDays < 0 {
+		retentionDays = 30
+	}
+
+	return time.Duration(retentionDays) * 24 * time.Hour
+}
+
+/**
+ *	Returns time between background purge runs from PURGE_INTERVAL (e.g. "30m"), defaults to 1 hour.
+ *
+ *	@return time.Duration
+ */
+func GetPurgeInterval() time.Duration {
+	purgeInterval, parseError := time.ParseDuration(utils.Pick(os.Getenv("PURGE_INTERVAL"), "1h"))
+
+	if parseError != nil || purgeInterval <= 0 {
+		purgeInterval = time.Hour
+	}
+
+	return purgeInterval
+}
+
+/**
+ *	Returns supported statement languages from comma separated LANGUAGES, defaults to "sv,en,no".
+ *	@NOTE First language is the language of statement bodies.
+ *
+ *	@return []string
+ */
+func GetLanguages() []string {
+	var languages []string
+
+	for _, language := range strings.Split(utils.Pick(os.Getenv("LANGUAGES"), "sv,en,no"), ",") {
+		language = strings.ToLower(strings.TrimSpace(language))
+
+		if language != "" {
+			languages = append(languages, language)
+		}
+	}
+
+	if len(languages) == 0 {
+		languages = append(languages, "sv")
+	}
+
+	return languages
+}
+
+/**
+ *	Returns default statement language, the first of GetLanguages.
+ *
+ *	@return string
+ */
+func GetDefaultLanguage() string {
+	return GetLanguages()[0]
+}
+
+/**
+ *	Returns true if language is one of GetLanguages.
+ *
+ *	@param language string
+ *
+ *	@return bool
+ */
+func IsSupportedLanguage(language string) bool {
+	for _, supportedLanguage := range GetLanguages() {
+		if supportedLanguage == strings.ToLower(language) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/**
+ *	Returns addresses of trusted reverse proxies from comma separated TRUSTED_PROXIES, empty by default.
+ *	@NOTE Forwarded client addresses are only read from requests sent by trusted proxies.
+ *
+ *	@return []string
+ */
+func GetTrustedProxies() []string {
+	var trustedProxies []string
+
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		proxy = strings.TrimSpace(proxy)
+
+		if proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	return trustedProxies
+}
+
+/**
+ *	Returns true if address is one of GetTrustedProxies.
+ *
+ *	@param address string - IP address.
+ *
+ *	@return bool
+ */
+func IsTrustedProxy(address string) bool {
+	for _, proxy := range GetTrustedProxies() {
+		if proxy == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+/**
+ *	Returns origins allowed to open WebSockets from comma separated WEBSOCKET_ORIGINS, e.g. "https://app.example.com", empty by default.
+ *	@NOTE Same origin requests are always allowed, see websocket.IsAllowedOrigin.
+ *
+ *	@return []string
+ */
+func GetWebSocketOrigins() []string {
+	var origins []string
+
+	for _, origin := range strings.Split(os.Getenv("WEBSOCKET_ORIGINS"), ",") {
+		origin = strings.TrimSpace(origin)
+
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	return origins
+}
+
 /**
  *	Returns true if MODE is set to ENV_PRODUCTION.
  *
@@ -0,0 +1,141 @@
+package events
+
+import (
+	// Native packages
+	"sync"
+)
+
+const SUBSCRIPTION_BUFFER_SIZE = 16
+
+type Event struct {
+	Name string
+	Data interface{}
+}
+
+type Subscription struct {
+	Topic  string
+	Events chan Event
+}
+
+type Hub struct {
+	mutex         sync.RWMutex
+	subscriptions map[string]map[*Subscription]bool
+}
+
+var hub *Hub
+var initOnce sync.Once
+
+/**
+ *	Returns in-process hub instance, creates a new instance if not set.
+ *
+ *	@return *Hub
+ */
+func GetHub() *Hub {
+	initOnce.Do(func() {
+		hub = NewHub()
+	})
+
+	return hub
+}
+
+/**
+ *	Creates a new hub without subscriptions.
+ *
+ *	@return *Hub
+ */
+func NewHub() *Hub {
+	return &Hub{
+		subscriptions: make(map[string]map[*Subscription]bool),
+	}
+}
+
+/**
+ *	Subscribes to topic, events are received on subscription channel until unsubscribed.
+ *
+ *	@param topic string
+ *
+ *	@return *Subscription
+ */
+func (hub *Hub) Subscribe(topic string) *Subscription {
+	subscription := &Subscription{
+		Topic:  topic,
+		Events: make(chan Event, SUBSCRIPTION_BUFFER_SIZE),
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if _, hasTopic := hub.subscriptions[topic]; !hasTopic {
+		hub.subscriptions[topic] = make(map[*Subscription]bool)
+	}
+
+	hub.subscriptions[topic][subscription] = true
+
+	return subscription
+}
+
+/**
+ *	Removes subscription from hub and closes its channel, safe to call more than once.
+ *
+ *	@param subscription *Subscription
+ *
+ *	@return void
+ */
+func (hub *Hub) Unsubscribe(subscription *Subscription) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	topicSubscriptions, hasTopic := hub.subscriptions[subscription.Topic]
+
+	if !hasTopic || !topicSubscriptions[subscription] {
+		return
+	}
+
+	delete(topicSubscriptions, subscription)
+	close(subscription.Events)
+
+	if len(topicSubscriptions) == 0 {
+		delete(hub.subscriptions, subscription.Topic)
+	}
+}
+
+/**
+ *	Publishes event to all topic subscribers.
+ *	@NOTE Publishing never blocks, events are dropped for subscribers with a full buffer.
+ *
+ *	@param topic string
+ *	@param eventName string
+ *	@param eventData interface{}
+ *
+ *	@return void
+ */
+func (hub *Hub) Publish(topic string, eventName string, eventData interface{}) {
+	event := Event{
+		Name: eventName,
+		Data: eventData,
+	}
+
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+
+	for subscription := range hub.subscriptions[topic] {
+		select {
+		case subscription.Events <- event:
+		default:
+		}
+	}
+}
+
+/**
+ *	Returns number of subscribers for topic.
+ *
+ *	@param topic string
+ *
+ *	@return int
+ */
+func (hub *Hub) CountSubscribers(topic string) int {
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+
+	return len(hub.subscriptions[topic])
+}
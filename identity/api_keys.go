@@ -0,0 +1,82 @@
+package identity
+
+import (
+	// Native packages
+	"time"
+
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+
+	// Local packages
+	"jaha-api/models"
+	"jaha-api/utils"
+)
+
+const API_KEY_HEADER = "X-API-Key"
+const API_KEY_CONTEXT_KEY = "apiKey"
+const API_KEY_LENGTH = 40
+const API_KEY_PREFIX_LENGTH = 8
+
+// @NOTE Last used timestamp is only written once per interval to spare a write on every request.
+const API_KEY_LAST_USED_INTERVAL = time.Minute
+
+/**
+ *	Returns a new unsaved API key for user and its unhashed key, unhashed key cannot be recovered later.
+ *
+ *	@param user models.User - Key owner.
+ *	@param name string - Key name.
+ *	@param role int - Role ceiling of key.
+ *
+ *	@return string, models.ApiKey
+ */
+func NewApiKey(user models.User, name string, role int) (string, models.ApiKey) {
+	rawKey := utils.RandomString(API_KEY_LENGTH)
+
+	apiKey := models.ApiKey{
+		UUID:    utils.RandomString(8),
+		UserId:  user.ID,
+		Name:    name,
+		Prefix:  rawKey[:API_KEY_PREFIX_LENGTH],
+		KeyHash: utils.HashToken(rawKey),
+		Role:    role,
+	}
+
+	return rawKey, apiKey
+}
+
+/**
+ *	Returns active API key matching unhashed key and updates its last used timestamp, false if key is unknown or revoked.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param rawKey string - Unhashed key from request.
+ *
+ *	@return models.ApiKey, bool
+ */
+func AuthenticateApiKey(dbc *gorm.DB, rawKey string) (models.ApiKey, bool) {
+	var apiKey models.ApiKey
+	var owner models.User
+
+	if len(rawKey) != API_KEY_LENGTH {
+		return apiKey, false
+	}
+
+	dbc.Where("`key_hash` = ? AND `revoked_at` IS NULL", utils.HashToken(rawKey)).First(&apiKey)
+
+	if apiKey.ID == 0 {
+		return apiKey, false
+	}
+
+	dbc.First(&owner, apiKey.UserId)
+
+	if !owner.CanAuthenticate() {
+		return apiKey, false
+	}
+
+	now := time.Now()
+
+	if !apiKey.LastUsedAt.Valid || now.Sub(apiKey.LastUsedAt.Time) > API_KEY_LAST_USED_INTERVAL {
+		dbc.Model(&apiKey).UpdateColumn("last_used_at", now)
+	}
+
+	return apiKey, true
+}
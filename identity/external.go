@@ -0,0 +1,124 @@
+package identity
+
+import (
+	// Native packages
+	"errors"
+	"strings"
+	"time"
+
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/models"
+	"jaha-api/oidc"
+	"jaha-api/utils"
+)
+
+var ErrUnverifiedEmail = errors.New("External identity email is not verified.")
+
+/**
+ *	Returns user linked to an external identity, users are created on first login.
+ *	@NOTE Existing users are only linked by email if provider has verified the email, otherwise anyone could claim an account.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param provider string - Provider name.
+ *	@param claims oidc.Claims - Validated ID token claims.
+ *
+ *	@return models.User, error
+ */
+func FindOrCreateExternalUser(dbc *gorm.DB, provider string, claims oidc.Claims) (models.User, error) {
+	var userIdentity models.UserIdentity
+	var user models.User
+
+	now := time.Now()
+
+	dbc.Where("`provider` = ? AND `subject` = ?", provider, claims.Subject).First(&userIdentity)
+
+	if userIdentity.ID != 0 {
+		dbc.First(&user, userIdentity.UserId)
+
+		if user.ID == 0 {
+			return user, errors.New("External identity is linked to a removed user.")
+		}
+
+		return user, dbc.Model(&userIdentity).UpdateColumns(map[string]interface{}{
+			"email":         claims.Email,
+			"last_login_at": now,
+		}).Error
+	}
+
+	if claims.Email != "" {
+		dbc.Unscoped().Where("`email` = ?", claims.Email).First(&user)
+	}
+
+	if user.DeletedAt.Valid {
+		return models.User{}, errors.New("External identity email belongs to a removed user.")
+	}
+
+	if user.ID != 0 && !bool(claims.EmailVerified) {
+		return models.User{}, ErrUnverifiedEmail
+	}
+
+	if user.ID == 0 {
+		if claims.Email == "" {
+			return user, errors.New("External identity has no email.")
+		}
+
+		user = newExternalUser(claims)
+
+		if bool(claims.EmailVerified) {
+			user.VerifiedAt = null.TimeFrom(now)
+		}
+
+		if createError := dbc.Create(&user).Error; createError != nil {
+			return user, createError
+		}
+	}
+
+	userIdentity = models.UserIdentity{
+		UserId:      user.ID,
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: null.TimeFrom(now),
+	}
+
+	return user, dbc.Create(&userIdentity).Error
+}
+
+/**
+ *	Returns unsaved user from external identity claims, names fall back to email local part.
+ *	@NOTE Password is left empty, external users cannot log in with a password until they reset it.
+ *
+ *	@param claims oidc.Claims - Validated ID token claims.
+ *
+ *	@return models.User
+ */
+func newExternalUser(claims oidc.Claims) models.User {
+	firstName := claims.GivenName
+	lastName := claims.FamilyName
+
+	// @NOTE Name may be only whitespace, which has no fields.
+	if nameParts := strings.Fields(claims.Name); firstName == "" && len(nameParts) > 0 {
+		firstName = nameParts[0]
+
+		if lastName == "" && len(nameParts) > 1 {
+			lastName = strings.Join(nameParts[1:], " ")
+		}
+	}
+
+	if firstName == "" {
+		firstName = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+
+	return models.User{
+		UUID:      utils.RandomString(8),
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     claims.Email,
+		AuthKey:   utils.RandomString(16),
+		Role:      models.USER_ROLE_GUEST,
+	}
+}
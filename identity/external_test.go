@@ -0,0 +1,81 @@
+package identity
+
+import (
+	// Native packages
+	"testing"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/env"
+	"jaha-api/oidc"
+	"jaha-api/oidc/oidctest"
+	"jaha-api/utils"
+)
+
+func TestNewExternalUserNames(t *testing.T) {
+	cases := map[string][2]string{
+		"Jane Doe":       {"Jane", "Doe"},
+		"Jane Ann Doe":   {"Jane", "Ann Doe"},
+		"Jane":           {"Jane", ""},
+		" \t ":           {"jane", ""},
+		"":               {"jane", ""},
+		"  Jane   Doe  ": {"Jane", "Doe"},
+	}
+
+	for name, expected := range cases {
+		user := newExternalUser(oidc.Claims{Name: name, Email: "jane@example.com"})
+
+		if user.FirstName != expected[0] || user.LastName != expected[1] {
+			t.Errorf("Name %q gave %q %q, expected %q %q", name, user.FirstName, user.LastName, expected[0], expected[1])
+		}
+	}
+}
+
+// @NOTE Needs a database with schema.sql loaded, set DSN to run.
+func TestFindOrCreateExternalUser(t *testing.T) {
+	if env.GetDatabaseSourceName() == "" {
+		t.Skip("DSN is not set.")
+	}
+
+	server := oidctest.NewServer("client", "secret")
+	defer server.Close()
+
+	email := utils.RandomString(8) + "@example.com"
+	flow := oidc.NewFlow()
+	server.Claims = oidc.Claims{Subject: utils.RandomString(16), Nonce: flow.Nonce, Email: email, EmailVerified: true, Name: " "}
+
+	claims, exchangeError := server.Provider("http://localhost/callback").Exchange(server.Code, flow)
+
+	if exchangeError != nil {
+		t.Fatalf("Exchange failed: %s", exchangeError)
+	}
+
+	dbc := db.GetConnection()
+	dbc.SingularTable(true)
+
+	tx := dbc.Begin()
+	defer tx.Rollback()
+
+	user, linkError := FindOrCreateExternalUser(tx, "mock", claims)
+
+	if linkError != nil {
+		t.Fatalf("First login failed: %s", linkError)
+	}
+
+	if user.ID == 0 || user.Email != email || !user.VerifiedAt.Valid {
+		t.Errorf("First login created unexpected user %+v", user)
+	}
+
+	linkedUser, linkError := FindOrCreateExternalUser(tx, "mock", claims)
+
+	if linkError != nil || linkedUser.ID != user.ID {
+		t.Errorf("Second login returned User#%d (%v), expected User#%d", linkedUser.ID, linkError, user.ID)
+	}
+
+	claims.Subject = utils.RandomString(16)
+	claims.EmailVerified = false
+
+	if _, linkError = FindOrCreateExternalUser(tx, "mock", claims); linkError != ErrUnverifiedEmail {
+		t.Errorf("Unverified email linked existing user, got %v", linkError)
+	}
+}
@@ -0,0 +1,67 @@
+package identity
+
+import (
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+	"gopkg.in/appleboy/gin-jwt.v2"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/models"
+)
+
+/**
+ *	Returns API key the current request is authenticated with, see middlewares.ApiKeyAuth.
+ *
+ *	@param ctx *gin.Context - Gin context.
+ *
+ *	@return models.ApiKey, bool
+ */
+func GetApiKey(ctx *gin.Context) (models.ApiKey, bool) {
+	if contextApiKey, hasApiKey := ctx.Get(API_KEY_CONTEXT_KEY); hasApiKey {
+		apiKey, isApiKey := contextApiKey.(models.ApiKey)
+		return apiKey, isApiKey
+	}
+
+	return models.ApiKey{}, false
+}
+
+/**
+ *	Returns user bound to current API key or JWT, user ID is 0 for guests.
+ *
+ *	@param ctx *gin.Context - Gin context.
+ *
+ *	@return models.User
+ */
+func GetUser(ctx *gin.Context) models.User {
+	var user models.User
+
+	// @NOTE Requests authenticated by API key act as key owner, capped to key role.
+	if apiKey, hasApiKey := GetApiKey(ctx); hasApiKey {
+		db.GetConnection().First(&user, apiKey.UserId)
+
+		if user.Role > apiKey.Role {
+			user.Role = apiKey.Role
+		}
+
+		return user
+	}
+
+	// @NOTE Claims are only set by JWT middleware, "id" claim is user UUID.
+	if userId, hasClaim := jwt.ExtractClaims(ctx)["id"].(string); hasClaim && userId != "" {
+		db.GetConnection().Where("`uuid` = ?", userId).First(&user)
+	}
+
+	return user
+}
+
+/**
+ *	Returns true if current request is bound to a user.
+ *
+ *	@param ctx *gin.Context - Gin context.
+ *
+ *	@return bool
+ */
+func IsAuthenticated(ctx *gin.Context) bool {
+	return GetUser(ctx).ID != 0
+}
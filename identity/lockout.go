@@ -0,0 +1,203 @@
+package identity
+
+import (
+	// Native packages
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+
+	// Local packages
+	"jaha-api/env"
+	"jaha-api/models"
+)
+
+/**
+ *	Returns normalized email used as lockout subject.
+ *
+ *	@param userEmail string - Email as entered.
+ *
+ *	@return string
+ */
+func NormalizeLoginEmail(userEmail string) string {
+	return strings.ToLower(strings.TrimSpace(userEmail))
+}
+
+/**
+ *	Returns client IP address used as lockout subject.
+ *	@NOTE Forwarding headers can be set by any client, "X-Forwarded-For" is only read behind a trusted proxy, see env.GetTrustedProxies.
+ *
+ *	@param ctx *gin.Context - Gin context.
+ *
+ *	@return string
+ */
+func GetClientIp(ctx *gin.Context) string {
+	clientIp, _, splitError := net.SplitHostPort(strings.TrimSpace(ctx.Request.RemoteAddr))
+
+	if splitError != nil {
+		clientIp = strings.TrimSpace(ctx.Request.RemoteAddr)
+	}
+
+	if !env.IsTrustedProxy(clientIp) {
+		return clientIp
+	}
+
+	// @NOTE Proxies append the address they received the request from, nearest untrusted address is the client.
+	forwardedIps := strings.Split(ctx.Request.Header.Get("X-Forwarded-For"), ",")
+
+	for index := len(forwardedIps) - 1; index >= 0; index-- {
+		forwardedIp := strings.TrimSpace(forwardedIps[index])
+
+		if forwardedIp == "" {
+			break
+		}
+
+		clientIp = forwardedIp
+
+		if !env.IsTrustedProxy(forwardedIp) {
+			break
+		}
+	}
+
+	return clientIp
+}
+
+/**
+ *	Checks whether login attempt is allowed before password is verified.
+ *	Returns 0 if allowed, http.StatusLocked if either subject is locked out or http.StatusTooManyRequests during backoff.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param userEmail string - Email attempting to log in.
+ *	@param clientIp string - Client IP address.
+ *
+ *	@return int, time.Duration
+ */
+func CheckLogin(dbc *gorm.DB, userEmail string, clientIp string) (int, time.Duration) {
+	var lockouts models.LoginLockouts
+
+	status := 0
+	retryAfter := time.Duration(0)
+	now := time.Now()
+
+	dbc.Where(
+		"(`subject_type` = ? AND `subject` = ?) OR (`subject_type` = ? AND `subject` = ?)",
+		models.LOGIN_SUBJECT_EMAIL, NormalizeLoginEmail(userEmail), models.LOGIN_SUBJECT_IP, clientIp,
+	).Find(&lockouts)
+
+	for _, lockout := range lockouts {
+		lockoutRetryAfter := lockout.RetryAfter(now)
+
+		if lockoutRetryAfter == 0 {
+			continue
+		}
+
+		if lockout.IsLocked(now) {
+			status = http.StatusLocked
+		} else if status == 0 {
+			status = http.StatusTooManyRequests
+		}
+
+		if lockoutRetryAfter > retryAfter {
+			retryAfter = lockoutRetryAfter
+		}
+	}
+
+	return status, retryAfter
+}
+
+/**
+ *	Counts failed login attempt for email and client IP, subjects reaching their attempt limit are locked out.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param userEmail string - Email attempting to log in.
+ *	@param clientIp string - Client IP address.
+ *
+ *	@return error
+ */
+func RecordLoginFailure(dbc *gorm.DB, userEmail string, clientIp string) error {
+	subjects := map[string]string{
+		models.LOGIN_SUBJECT_EMAIL: NormalizeLoginEmail(userEmail),
+		models.LOGIN_SUBJECT_IP:    clientIp,
+	}
+
+	for subjectType, subject := range subjects {
+		// @NOTE MySQL assigns left to right, so "locked_until" sees the updated "failure_count".
+		recordError := dbc.Exec(
+			"INSERT INTO `login_lockout` (`subject_type`, `subject`, `failure_count`, `last_failure_at`) VALUES (?, ?, 1, NOW()) "+
+				"ON DUPLICATE KEY UPDATE "+
+				"`failure_count` = IF(`last_failure_at` < NOW() - INTERVAL ? SECOND OR `locked_until` < NOW(), 1, `failure_count` + 1), "+
+				"`last_failure_at` = NOW(), "+
+				"`locked_until` = IF(`failure_count` >= ?, NOW() + INTERVAL ? SECOND, NULL)",
+			subjectType,
+			subject,
+			int(models.LOGIN_FAILURE_WINDOW.Seconds()),
+			models.GetLoginLockoutAttempts(subjectType),
+			int(models.LOGIN_LOCKOUT_DURATION.Seconds()),
+		).Error
+
+		if recordError != nil {
+			return recordError
+		}
+	}
+
+	return nil
+}
+
+/**
+ *	Clears failed login attempts of email after a successful login.
+ *	@NOTE Client IP failures are kept, one valid account must not reset attempts against other accounts.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param userEmail string - Email that logged in.
+ *
+ *	@return error
+ */
+func RecordLoginSuccess(dbc *gorm.DB, userEmail string) error {
+	return ClearLoginLockout(dbc, userEmail)
+}
+
+/**
+ *	Returns lockout state of email, ID is 0 if there are no failed attempts.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param userEmail string - User email.
+ *
+ *	@return models.LoginLockout
+ */
+func FindLoginLockout(dbc *gorm.DB, userEmail string) models.LoginLockout {
+	var lockout models.LoginLockout
+
+	dbc.Where("`subject_type` = ? AND `subject` = ?", models.LOGIN_SUBJECT_EMAIL, NormalizeLoginEmail(userEmail)).First(&lockout)
+	lockout.Locked = lockout.IsLocked(time.Now())
+
+	return lockout
+}
+
+/**
+ *	Removes failed login attempts and lockout of email.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param userEmail string - User email.
+ *
+ *	@return error
+ */
+func ClearLoginLockout(dbc *gorm.DB, userEmail string) error {
+	return ClearLoginLockoutSubject(dbc, models.LOGIN_SUBJECT_EMAIL, NormalizeLoginEmail(userEmail))
+}
+
+/**
+ *	Removes failed login attempts and lockout of any subject, e.g. a client IP address.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param subjectType string - LOGIN_SUBJECT_EMAIL or LOGIN_SUBJECT_IP.
+ *	@param subject string - Normalized email or IP address.
+ *
+ *	@return error
+ */
+func ClearLoginLockoutSubject(dbc *gorm.DB, subjectType string, subject string) error {
+	return dbc.Where("`subject_type` = ? AND `subject` = ?", subjectType, subject).Delete(&models.LoginLockout{}).Error
+}
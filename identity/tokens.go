@@ -0,0 +1,262 @@
+package identity
+
+import (
+	// Native packages
+	"errors"
+	"strings"
+	"time"
+
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+	"gopkg.in/dgrijalva/jwt-go.v3"
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/env"
+	"jaha-api/models"
+	"jaha-api/utils"
+)
+
+const ACCESS_TOKEN_TIMEOUT = time.Hour
+const REFRESH_TOKEN_TIMEOUT = 30 * 24 * time.Hour
+const REFRESH_TOKEN_LENGTH = 48
+const TOKEN_ID_LENGTH = 16
+
+var ErrRefreshTokenRevoked = errors.New("Refresh token is already revoked.")
+
+/**
+ *	Returns user matching email and password, false if credentials do not match.
+ *	@NOTE Suspended users are returned as authenticated, check models.User.CanAuthenticate before issuing tokens.
+ *
+ *	@param userEmail string - User email, used as username.
+ *	@param userPassword string - User password, unhashed.
+ *
+ *	@return models.User, bool
+ */
+func Authenticate(userEmail string, userPassword string) (models.User, bool) {
+	var user models.User
+
+	db.GetConnection().Where("`email` = ?", userEmail).First(&user)
+
+	if user.ID == 0 || user.AuthKey == "" || !utils.PasswordMatch(user.Password, userPassword) {
+		return user, false
+	}
+
+	return user, true
+}
+
+/**
+ *	Creates a signed access token, claims are compatible with the JWT auth middleware.
+ *	@NOTE "id" is user UUID so tokens survive email changes, "jti" identifies the token for revocation, "akh" binds the token to current user auth key.
+ *
+ *	@param user models.User - Token owner.
+ *
+ *	@return string, time.Time, error
+ */
+func CreateAccessToken(user models.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ACCESS_TOKEN_TIMEOUT)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":       user.UUID,
+		"exp":      expiresAt.Unix(),
+		"orig_iat": now.Unix(),
+		"jti":      utils.RandomString(TOKEN_ID_LENGTH),
+		"akh":      user.GetAuthKeyHash(),
+	})
+
+	signedToken, signError := token.SignedString([]byte(env.GetRealmKey()))
+
+	return signedToken, expiresAt, signError
+}
+
+/**
+ *	Creates and stores a refresh token, only the token hash is stored.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param user models.User - Token owner.
+ *
+ *	@return string, models.RefreshToken, error
+ */
+func CreateRefreshToken(dbc *gorm.DB, user models.User) (string, models.RefreshToken, error) {
+	rawToken := utils.RandomString(REFRESH_TOKEN_LENGTH)
+
+	refreshToken := models.RefreshToken{
+		UserId:    user.ID,
+		TokenHash: utils.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(REFRESH_TOKEN_TIMEOUT),
+	}
+
+	createError := dbc.Create(&refreshToken).Error
+
+	return rawToken, refreshToken, createError
+}
+
+/**
+ *	Finds stored refresh token by unhashed token, ID is 0 if not found.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param rawToken string - Unhashed refresh token.
+ *
+ *	@return models.RefreshToken
+ */
+func FindRefreshToken(dbc *gorm.DB, rawToken string) models.RefreshToken {
+	var refreshToken models.RefreshToken
+
+	if rawToken != "" {
+		dbc.Where("`token_hash` = ?", utils.HashToken(rawToken)).First(&refreshToken)
+	}
+
+	return refreshToken
+}
+
+/**
+ *	Revokes a refresh token, returns ErrRefreshTokenRevoked if it was revoked meanwhile, e.g. by a concurrent refresh with the same token.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param refreshToken *models.RefreshToken - Token to revoke.
+ *
+ *	@return error
+ */
+func RevokeRefreshToken(dbc *gorm.DB, refreshToken *models.RefreshToken) error {
+	revokedAt := time.Now()
+
+	query := dbc.Model(&models.RefreshToken{}).Where("`id` = ? AND `revoked_at` IS NULL", refreshToken.ID).UpdateColumn("revoked_at", revokedAt)
+
+	if query.Error != nil {
+		return query.Error
+	}
+
+	if query.RowsAffected == 0 {
+		return ErrRefreshTokenRevoked
+	}
+
+	refreshToken.RevokedAt = null.TimeFrom(revokedAt)
+
+	return nil
+}
+
+/**
+ *	Revokes all active refresh tokens of a user.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param userId int - Token owner ID.
+ *
+ *	@return error
+ */
+func RevokeRefreshTokens(dbc *gorm.DB, userId int) error {
+	return dbc.Model(&models.RefreshToken{}).Where("`user_id` = ? AND `revoked_at` IS NULL", userId).UpdateColumn("revoked_at", time.Now()).Error
+}
+
+/**
+ *	Adds access token to revocation list, tokens without "jti" claim cannot be revoked.
+ *	@NOTE Expired entries are removed from the list since expired tokens are rejected anyway.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param user models.User - Token owner.
+ *	@param claims map[string]interface{} - Access token claims.
+ *
+ *	@return error
+ */
+func RevokeAccessToken(dbc *gorm.DB, user models.User, claims map[string]interface{}) error {
+	tokenId, hasTokenId := claims["jti"].(string)
+
+	if !hasTokenId || tokenId == "" {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(ACCESS_TOKEN_TIMEOUT)
+
+	if expiresUnix, hasExpires := claims["exp"].(float64); hasExpires {
+		expiresAt = time.Unix(int64(expiresUnix), 0)
+	}
+
+	dbc.Where("`expires_at` < ?", time.Now()).Delete(&models.RevokedToken{})
+
+	return dbc.Exec("INSERT IGNORE INTO `revoked_token` (`jti`, `user_id`, `expires_at`) VALUES (?, ?, ?)", tokenId, user.ID, expiresAt).Error
+}
+
+/**
+ *	Returns true if user may authenticate, access token claims are bound to current user auth key and token is not revoked.
+ *
+ *	@param user models.User - Token owner.
+ *	@param claims map[string]interface{} - Access token claims.
+ *
+ *	@return bool
+ */
+func IsAccessTokenValid(user models.User, claims map[string]interface{}) bool {
+	var revokedCount int
+
+	authKeyHash, _ := claims["akh"].(string)
+	tokenId, _ := claims["jti"].(string)
+
+	if !user.CanAuthenticate() || authKeyHash != user.GetAuthKeyHash() || tokenId == "" {
+		return false
+	}
+
+	db.GetConnection().Model(&models.RevokedToken{}).Where("`jti` = ?", tokenId).Count(&revokedCount)
+
+	return revokedCount == 0
+}
+
+/**
+ *	Returns owner of access token from "Authorization" header, false if token is malformed, expired, signed with another key or no longer valid.
+ *	@NOTE Same checks as JWT auth middleware and AuthAuthorizator, for requests where invalid tokens are ignored instead of rejected.
+ *
+ *	@param authorization string - "Authorization" header, "Bearer <token>".
+ *
+ *	@return models.User, bool
+ */
+func ParseAccessToken(authorization string) (models.User, bool) {
+	var user models.User
+
+	parts := strings.SplitN(authorization, " ", 2)
+
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return user, false
+	}
+
+	token, parseError := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, errors.New("Invalid signing algorithm.")
+		}
+
+		return []byte(env.GetRealmKey()), nil
+	})
+
+	if parseError != nil || !token.Valid {
+		return user, false
+	}
+
+	claims, hasClaims := token.Claims.(jwt.MapClaims)
+	userId, hasUserId := claims["id"].(string)
+
+	if !hasClaims || !hasUserId || userId == "" {
+		return user, false
+	}
+
+	db.GetConnection().Where("`uuid` = ?", userId).First(&user)
+
+	return user, user.ID != 0 && IsAccessTokenValid(user, claims)
+}
+
+/**
+ *	Replaces user auth key, invalidating every access token and refresh token issued before.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param user *models.User - User to rotate auth key for.
+ *
+ *	@return error
+ */
+func RotateAuthKey(dbc *gorm.DB, user *models.User) error {
+	user.AuthKey = utils.RandomString(16)
+
+	updateError := dbc.Model(user).UpdateColumn("auth_key", user.AuthKey).Error
+
+	if updateError != nil {
+		return updateError
+	}
+
+	return RevokeRefreshTokens(dbc, user.ID)
+}
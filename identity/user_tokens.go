@@ -0,0 +1,100 @@
+package identity
+
+import (
+	// Native packages
+	"errors"
+	"time"
+
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/models"
+	"jaha-api/utils"
+)
+
+const USER_TOKEN_LENGTH = 32
+const RESET_TOKEN_TIMEOUT = time.Hour
+const VERIFY_TOKEN_TIMEOUT = 48 * time.Hour
+
+var ErrUserTokenUsed = errors.New("User token is already used.")
+
+/**
+ *	Creates and stores a single-use token, earlier unused tokens with same purpose are expired.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param user models.User - Token owner.
+ *	@param purpose string - Token purpose, USER_TOKEN_PURPOSE_RESET or USER_TOKEN_PURPOSE_VERIFY.
+ *
+ *	@return string, error
+ */
+func CreateUserToken(dbc *gorm.DB, user models.User, purpose string) (string, error) {
+	timeout := VERIFY_TOKEN_TIMEOUT
+
+	if purpose == models.USER_TOKEN_PURPOSE_RESET {
+		timeout = RESET_TOKEN_TIMEOUT
+	}
+
+	expireError := dbc.Model(&models.UserToken{}).Where("`user_id` = ? AND `purpose` = ? AND `used_at` IS NULL", user.ID, purpose).UpdateColumn("expires_at", time.Now()).Error
+
+	if expireError != nil {
+		return "", expireError
+	}
+
+	rawToken := utils.RandomString(USER_TOKEN_LENGTH)
+
+	userToken := models.UserToken{
+		UserId:    user.ID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(timeout),
+	}
+
+	return rawToken, dbc.Create(&userToken).Error
+}
+
+/**
+ *	Finds stored token by unhashed token and purpose, ID is 0 if not found.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param rawToken string - Unhashed token.
+ *	@param purpose string - Token purpose.
+ *
+ *	@return models.UserToken
+ */
+func FindUserToken(dbc *gorm.DB, rawToken string, purpose string) models.UserToken {
+	var userToken models.UserToken
+
+	if rawToken != "" {
+		dbc.Where("`token_hash` = ? AND `purpose` = ?", utils.HashToken(rawToken), purpose).First(&userToken)
+	}
+
+	return userToken
+}
+
+/**
+ *	Marks token as used, returns ErrUserTokenUsed if a concurrent request used it first.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param userToken *models.UserToken - Token to use.
+ *
+ *	@return error
+ */
+func UseUserToken(dbc *gorm.DB, userToken *models.UserToken) error {
+	usedAt := time.Now()
+
+	query := dbc.Model(&models.UserToken{}).Where("`id` = ? AND `used_at` IS NULL", userToken.ID).UpdateColumn("used_at", usedAt)
+
+	if query.Error != nil {
+		return query.Error
+	}
+
+	if query.RowsAffected == 0 {
+		return ErrUserTokenUsed
+	}
+
+	userToken.UsedAt = null.TimeFrom(usedAt)
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package mail
+
+import (
+	// Native packages
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	// Local packages
+	"jaha-api/utils"
+)
+
+/**
+ *	Writes messages as .eml files to a local directory, stand-in for a mail service in development.
+ */
+type FileSender struct {
+	Directory string
+	From      string
+}
+
+/**
+ *	Returns new file sender.
+ *
+ *	@param directory string - Directory to write messages to, created if missing.
+ *	@param from string - Sender address.
+ *
+ *	@return *FileSender
+ */
+func NewFileSender(directory string, from string) *FileSender {
+	return &FileSender{
+		Directory: directory,
+		From:      from,
+	}
+}
+
+func (sender *FileSender) Send(message Message) error {
+	if mkdirError := os.MkdirAll(sender.Directory, 0755); mkdirError != nil {
+		return mkdirError
+	}
+
+	now := time.Now()
+	fileName := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405"), utils.RandomString(8))
+
+	return ioutil.WriteFile(filepath.Join(sender.Directory, fileName), formatMessage(sender.From, message, now), 0644)
+}
+
+/**
+ *	Returns message in RFC 5322 format.
+ *
+ *	@param from string - Sender address.
+ *	@param message Message - Message to format.
+ *	@param date time.Time - Message date.
+ *
+ *	@return []byte
+ */
+func formatMessage(from string, message Message, date time.Time) []byte {
+	return []byte(fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n",
+		from,
+		message.To,
+		message.Subject,
+		date.Format(time.RFC1123Z),
+		message.Body,
+	))
+}
@@ -0,0 +1,77 @@
+package mail
+
+import (
+	// Native packages
+	"sync"
+
+	// Local packages
+	"jaha-api/env"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+/**
+ *	Mail sender interface, implement to deliver mail through another service.
+ */
+type Sender interface {
+	Send(message Message) error
+}
+
+var currentSender Sender
+var senderLock sync.RWMutex
+
+/**
+ *	Returns current mail sender, defaults to sender set by MAIL_SENDER environment variable.
+ *
+ *	@return Sender
+ */
+func GetSender() Sender {
+	senderLock.RLock()
+	sender := currentSender
+	senderLock.RUnlock()
+
+	if sender != nil {
+		return sender
+	}
+
+	senderLock.Lock()
+	defer senderLock.Unlock()
+
+	if currentSender == nil {
+		if env.GetMailSender() == env.MAIL_SENDER_SMTP {
+			currentSender = NewSmtpSender(env.GetSmtpAddress(), env.GetSmtpUsername(), env.GetSmtpPassword(), env.GetMailFrom())
+		} else {
+			currentSender = NewFileSender(env.GetMailDirectory(), env.GetMailFrom())
+		}
+	}
+
+	return currentSender
+}
+
+/**
+ *	Replaces current mail sender.
+ *
+ *	@param sender Sender - Mail sender.
+ *
+ *	@return void
+ */
+func SetSender(sender Sender) {
+	senderLock.Lock()
+	currentSender = sender
+	senderLock.Unlock()
+}
+
+/**
+ *	Sends message using current mail sender.
+ *
+ *	@param message Message - Message to send.
+ *
+ *	@return error
+ */
+func Send(message Message) error {
+	return GetSender().Send(message)
+}
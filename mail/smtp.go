@@ -0,0 +1,53 @@
+package mail
+
+import (
+	// Native packages
+	"net"
+	"net/smtp"
+	"time"
+)
+
+/**
+ *	Sends messages through an SMTP server, authenticates with PLAIN auth if username is set.
+ */
+type SmtpSender struct {
+	Address  string
+	Username string
+	Password string
+	From     string
+}
+
+/**
+ *	Returns new SMTP sender.
+ *
+ *	@param address string - SMTP server address, "host:port".
+ *	@param username string - SMTP username, empty to skip authentication.
+ *	@param password string - SMTP password.
+ *	@param from string - Sender address.
+ *
+ *	@return *SmtpSender
+ */
+func NewSmtpSender(address string, username string, password string, from string) *SmtpSender {
+	return &SmtpSender{
+		Address:  address,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+func (sender *SmtpSender) Send(message Message) error {
+	var auth smtp.Auth
+
+	if sender.Username != "" {
+		host, _, splitError := net.SplitHostPort(sender.Address)
+
+		if splitError != nil {
+			return splitError
+		}
+
+		auth = smtp.PlainAuth("", sender.Username, sender.Password, host)
+	}
+
+	return smtp.SendMail(sender.Address, auth, sender.From, []string{message.To}, formatMessage(sender.From, message, time.Now()))
+}
@@ -3,6 +3,8 @@ package main
 import (
 	// Native packages
 	"fmt"
+	"log"
+	"os"
 
 	// 3rd party packages
 	"github.com/gin-gonic/contrib/sessions"
@@ -10,6 +12,7 @@ import (
 	// Local packages
 	"jaha-api/db"
 	"jaha-api/env"
+	"jaha-api/purge"
 	"jaha-api/routers"
 )
 
@@ -22,6 +25,16 @@ func main() {
 		dbc.LogMode(true)
 	}
 
+	// @NOTE "jaha-api purge" runs a single purge and exits, see docs/retention.md.
+	if len(os.Args) > 1 && os.Args[1] == "purge" {
+		runPurge()
+		return
+	}
+
+	if env.GetRetentionPeriod() > 0 {
+		purge.Start(dbc, env.GetRetentionPeriod(), env.GetPurgeInterval())
+	}
+
 	sessionStore := sessions.NewCookieStore([]byte(env.GetSessionKey()))
 	sessionManager := sessions.Sessions(env.GetRealmKey(), sessionStore)
 
@@ -31,3 +44,22 @@ func main() {
 
 	defaultRouter.Run(":" + env.GetPort())
 }
+
+/**
+ *	Purges soft deleted rows older than retention period once and prints what was removed.
+ *
+ *	@return void
+ */
+func runPurge() {
+	if env.GetRetentionPeriod() == 0 {
+		log.Fatal("Purging is disabled, RETENTION_DAYS is 0.")
+	}
+
+	report, purgeError := purge.Run(db.GetConnection(), env.GetRetentionPeriod())
+
+	fmt.Println(fmt.Sprintf("[%s] Purged %s", env.GetAppName(), report))
+
+	if purgeError != nil {
+		log.Fatal(purgeError)
+	}
+}
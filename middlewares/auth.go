@@ -1,66 +1,32 @@
 package middlewares
 
 import (
-	// Native packages
-	"time"
-
 	// 3rd party packages
-	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"gopkg.in/appleboy/gin-jwt.v2"
 
 	// Local packages
 	"jaha-api/db"
 	"jaha-api/env"
+	"jaha-api/identity"
 	"jaha-api/models"
 	"jaha-api/responders"
-	"jaha-api/utils"
 )
 
 /**
- *	Authentication middleware, validates user and user password.
- *
- *	@param userEmail string - User email, used as username.
- *	@param userPassword string - User password, unhashed.
- *	@param ctx *gin.Context - Gin context.
- *
- *	@return string, bool
- */
-func AuthAuthenticator(userEmail string, userPassword string, ctx *gin.Context) (string, bool) {
-	var user models.User
-
-	db.GetConnection().Where("`email` = ?", userEmail).First(&user)
-	passwordMatches := utils.PasswordMatch(user.Password, userPassword)
-
-	ctx.Set("userId", user.UUID)
-
-	return userEmail, passwordMatches
-}
-
-/**
- *	Authorizator middleware, validates whether or
+ *	Authorizator middleware, validates whether user exists and token is neither revoked nor issued for a rotated auth key.
  *
- *	@param userEmail string - User email, used as username.
+ *	@param userId string - User UUID, "id" claim of access token.
  *	@param ctx *gin.Context - Gin context.
  *
  *	@return bool
  */
-func AuthAuthorizator(userEmail string, ctx *gin.Context) bool {
+func AuthAuthorizator(userId string, ctx *gin.Context) bool {
 	var user models.User
 
-	db.GetConnection().Where("`email` = ?", userEmail).First(&user)
-
-	session := sessions.Default(ctx)
-
-	// @NOTE Set user ID even if it's nil.
-	session.Set("userId", user.ID)
-	session.Save()
+	db.GetConnection().Where("`uuid` = ?", userId).First(&user)
 
-	if user.AuthKey != "" {
-		return true
-	}
-
-	return false
+	return user.ID != 0 && identity.IsAccessTokenValid(user, jwt.ExtractClaims(ctx))
 }
 
 /**
@@ -86,15 +52,95 @@ func AuthMiddleware() *jwt.GinJWTMiddleware {
 	realmKey := env.GetRealmKey()
 
 	middleware := &jwt.GinJWTMiddleware{
-		Realm:         env.GetAppName(),
-		Key:           []byte(realmKey),
-		Timeout:       time.Hour,
-		MaxRefresh:    time.Hour,
-		Authenticator: AuthAuthenticator,
-		Authorizator:  AuthAuthorizator,
-		Unauthorized:  AuthUnauthorized,
-		TokenLookup:   "header:Authorization",
+		Realm:        env.GetAppName(),
+		Key:          []byte(realmKey),
+		Timeout:      identity.ACCESS_TOKEN_TIMEOUT,
+		Authorizator: AuthAuthorizator,
+		Unauthorized: AuthUnauthorized,
+		TokenLookup:  "header:Authorization",
 	}
 
 	return middleware
 }
+
+/**
+ *	Returns authentication middleware for WebSocket clients.
+ *	@NOTE Browsers cannot set headers on WebSocket requests, token is read from "Authorization" header or "token" query parameter.
+ *
+ *	@return gin.HandlerFunc
+ */
+func SocketAuth() gin.HandlerFunc {
+	headerAuth := AuthMiddleware().MiddlewareFunc()
+
+	queryMiddleware := AuthMiddleware()
+	queryMiddleware.TokenLookup = "query:token"
+	queryAuth := queryMiddleware.MiddlewareFunc()
+
+	return func(ctx *gin.Context) {
+		if ctx.Request.Header.Get("Authorization") != "" {
+			headerAuth(ctx)
+			return
+		}
+
+		queryAuth(ctx)
+	}
+}
+
+/**
+ *	Returns authentication middleware accepting either an API key in "X-API-Key" header or a JWT.
+ *	@NOTE API key wins when both are sent, JWT middleware is only invoked without an API key.
+ *
+ *	@param jwtAuth gin.HandlerFunc - JWT authentication middleware.
+ *
+ *	@return gin.HandlerFunc
+ */
+func ApiKeyAuth(jwtAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rawKey := ctx.Request.Header.Get(identity.API_KEY_HEADER)
+
+		if rawKey == "" {
+			jwtAuth(ctx)
+			return
+		}
+
+		apiKey, keyValid := identity.AuthenticateApiKey(db.GetConnection(), rawKey)
+
+		if !keyValid {
+			responders.Text().Unauthorized(ctx, "Invalid API key.")
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(identity.API_KEY_CONTEXT_KEY, apiKey)
+		ctx.Next()
+	}
+}
+
+/**
+ *	Returns middleware that only authenticates requests sending valid credentials, other requests pass through as anonymous.
+ *	@NOTE Used on public endpoints that show more to authenticated users, a stale token or revoked API key must not break them.
+ *	@NOTE API key wins when both are sent, like in ApiKeyAuth.
+ *
+ *	@param jwtAuth gin.HandlerFunc - JWT authentication middleware, only invoked for valid tokens.
+ *
+ *	@return gin.HandlerFunc
+ */
+func OptionalAuth(jwtAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if rawKey := ctx.Request.Header.Get(identity.API_KEY_HEADER); rawKey != "" {
+			if apiKey, keyValid := identity.AuthenticateApiKey(db.GetConnection(), rawKey); keyValid {
+				ctx.Set(identity.API_KEY_CONTEXT_KEY, apiKey)
+			}
+
+			ctx.Next()
+			return
+		}
+
+		if _, tokenValid := identity.ParseAccessToken(ctx.Request.Header.Get("Authorization")); !tokenValid {
+			ctx.Next()
+			return
+		}
+
+		jwtAuth(ctx)
+	}
+}
@@ -6,35 +6,28 @@ import (
 
 	// Local packages
 	"jaha-api/constraints"
+	"jaha-api/responders"
 )
 
 /**
- *	Loops through registered constraints (if any) and validates them.
+ *	Loops through registered constraints (if any) and validates those matching request route.
+ *	@NOTE Role and ownership checks live in permissions, see middlewares.Permissions.
  *
  *	@return gin.HandlerFunc
  */
 func Constraints() gin.HandlerFunc {
-	// @NOTE Manually invoke constraints register functions
-	constraints.UserConstraints()
-
 	return func(ctx *gin.Context) {
-		canContinueRequest := true
-		registeredConstraints := constraints.GetConstraints()
+		routeTemplate, hasRouteTemplate := getRouteTemplate(ctx)
 
-		if len(registeredConstraints) > 0 {
-			for _, constraint := range registeredConstraints {
-				if constraint.RequestMethod == ctx.Request.Method && constraint.RequestPath == ctx.Request.URL.Path {
-					if !constraint.Guard(ctx) {
-						canContinueRequest = false
-						break
-					}
-				}
-			}
+		if !hasRouteTemplate {
+			ctx.Next()
+			return
+		}
 
-			if !canContinueRequest {
-				ctx.JSON(403, gin.H{
-					"error": "Permission denied.",
-				})
+		for _, constraint := range constraints.GetConstraints() {
+			if constraint.Match(ctx.Request.Method, routeTemplate) && !constraint.Guard(ctx, getRouteParams(ctx)) {
+				responders.Text().Forbidden(ctx, "Permission denied.")
+				ctx.Abort()
 				return
 			}
 		}
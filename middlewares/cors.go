@@ -18,7 +18,7 @@ func Cors() gin.HandlerFunc {
 		ctx.Header("Access-Control-Max-Age", "86400")
 		ctx.Header("Access-Control-Allow-Credentials", "true")
 		ctx.Header("Access-Control-Allow-Methods", "OPTIONS, GET, POST, PUT, PATCH, DELETE")
-		ctx.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, Authorization, WWW-Authenticate, Accept, Origin, Cache-Control, X-Requested-With")
+		ctx.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, Authorization, WWW-Authenticate, Accept, Origin, Cache-Control, X-Requested-With, X-API-Key")
 
 		if ctx.Request.Method == "OPTIONS" {
 			ctx.AbortWithStatus(204)
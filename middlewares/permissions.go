@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/identity"
+	"jaha-api/permissions"
+	"jaha-api/responders"
+)
+
+/**
+ *	Checks current user role and ownership against permission of matched route.
+ *	Routes without a permission mapping are denied, see permissions.Routes.
+ *
+ *	@return gin.HandlerFunc
+ */
+func Permissions() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		routeTemplate, _ := getRouteTemplate(ctx)
+		route, hasRoute := permissions.MatchRoute(ctx.Request.Method, routeTemplate)
+
+		if !hasRoute || !permissions.Can(identity.GetUser(ctx), route.Resource, route.Action, getRouteParams(ctx)) {
+			responders.Text().Forbidden(ctx, "Permission denied.")
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	// Native packages
+	"strings"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+)
+
+var registeredRoutes gin.RoutesInfo
+
+/**
+ *	Registers router routes, route templates are resolved from them for permissions and constraints.
+ *	@NOTE This function *must* be called in router once every route is attached.
+ *
+ *	@param routes gin.RoutesInfo - Registered routes, see gin.Engine.Routes.
+ *
+ *	@return void
+ */
+func SetRoutes(routes gin.RoutesInfo) {
+	registeredRoutes = routes
+}
+
+/**
+ *	Returns template of the route that router matched for request, e.g. "/v1/users/:uuid".
+ *	@NOTE Routes sharing a handler are told apart by expanding their template with resolved params.
+ *
+ *	@param ctx *gin.Context - Gin context.
+ *
+ *	@return string, bool
+ */
+func getRouteTemplate(ctx *gin.Context) (string, bool) {
+	handlerName := ctx.HandlerName()
+
+	for _, route := range registeredRoutes {
+		if route.Method != ctx.Request.Method || route.Handler != handlerName {
+			continue
+		}
+
+		if expandRouteTemplate(route.Path, ctx.Params) == ctx.Request.URL.Path {
+			return route.Path, true
+		}
+	}
+
+	return "", false
+}
+
+/**
+ *	Replaces named segments and wildcards of route template with resolved params.
+ *
+ *	@param routeTemplate string - Route template.
+ *	@param params gin.Params - Resolved route params.
+ *
+ *	@return string
+ */
+func expandRouteTemplate(routeTemplate string, params gin.Params) string {
+	segments := strings.Split(routeTemplate, "/")
+
+	for index, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[index], _ = params.Get(segment[1:])
+		}
+
+		if strings.HasPrefix(segment, "*") {
+			value, _ := params.Get(segment[1:])
+			segments[index] = strings.TrimPrefix(value, "/")
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+/**
+ *	Returns route params resolved by router, keyed by param name.
+ *
+ *	@param ctx *gin.Context - Gin context.
+ *
+ *	@return map[string]string
+ */
+func getRouteParams(ctx *gin.Context) map[string]string {
+	params := map[string]string{}
+
+	for _, param := range ctx.Params {
+		params[param.Key] = param.Value
+	}
+
+	return params
+}
@@ -0,0 +1,123 @@
+package middlewares
+
+import (
+	// Native packages
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// 3rd party packages
+	"github.com/gin-gonic/gin"
+
+	// Local packages
+	"jaha-api/controllers"
+)
+
+/**
+ *	Returns router recording resolved route template and params of each request, handlers are never reached.
+ */
+func newRouteTestRouter(resolved map[string]string, params map[string]map[string]string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		routeTemplate, hasRouteTemplate := getRouteTemplate(ctx)
+
+		if hasRouteTemplate {
+			resolved[ctx.Request.URL.Path] = routeTemplate
+			params[ctx.Request.URL.Path] = getRouteParams(ctx)
+		}
+
+		ctx.AbortWithStatus(http.StatusNoContent)
+	})
+
+	router.GET("/v1/users/:uuid", controllers.UsersController().Show)
+	router.GET("/v1/users/:uuid/keys", controllers.ApiKeysController().Index)
+	router.DELETE("/v1/users/:uuid/keys/:key", controllers.ApiKeysController().Destroy)
+	router.GET("/v1/statements/:uuid/revisions", controllers.StatementRevisionsController().Index)
+	router.GET("/v1/categories/:uuid/revisions", controllers.CategoryRevisionsController().Index)
+	router.GET("/v1/files/*path", controllers.DefaultController().MissingRoute)
+
+	SetRoutes(router.Routes())
+
+	return router
+}
+
+func TestGetRouteTemplate(t *testing.T) {
+	resolved := map[string]string{}
+	params := map[string]map[string]string{}
+	router := newRouteTestRouter(resolved, params)
+
+	// @NOTE Statement and category revisions share handler name, only their templates tell them apart.
+	handlerNames := map[string]string{}
+
+	for _, route := range router.Routes() {
+		handlerNames[route.Path] = route.Handler
+	}
+
+	if handlerNames["/v1/statements/:uuid/revisions"] != handlerNames["/v1/categories/:uuid/revisions"] {
+		t.Fatalf("Revision routes have handlers %q and %q, expected one shared handler", handlerNames["/v1/statements/:uuid/revisions"], handlerNames["/v1/categories/:uuid/revisions"])
+	}
+
+	cases := []struct {
+		method        string
+		path          string
+		routeTemplate string
+		params        map[string]string
+	}{
+		{"GET", "/v1/users/abcd1234", "/v1/users/:uuid", map[string]string{"uuid": "abcd1234"}},
+		{"GET", "/v1/users/abcd1234/keys", "/v1/users/:uuid/keys", map[string]string{"uuid": "abcd1234"}},
+		{"DELETE", "/v1/users/abcd1234/keys/efgh5678", "/v1/users/:uuid/keys/:key", map[string]string{"uuid": "abcd1234", "key": "efgh5678"}},
+		{"GET", "/v1/statements/abcd1234/revisions", "/v1/statements/:uuid/revisions", map[string]string{"uuid": "abcd1234"}},
+		{"GET", "/v1/categories/abcd1234/revisions", "/v1/categories/:uuid/revisions", map[string]string{"uuid": "abcd1234"}},
+		{"GET", "/v1/files/docs/rooms.md", "/v1/files/*path", map[string]string{"path": "/docs/rooms.md"}},
+	}
+
+	for _, testCase := range cases {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(testCase.method, testCase.path, nil))
+
+		if resolved[testCase.path] != testCase.routeTemplate {
+			t.Errorf("%s %s resolved to %q, expected %q", testCase.method, testCase.path, resolved[testCase.path], testCase.routeTemplate)
+		}
+
+		for key, value := range testCase.params {
+			if params[testCase.path][key] != value {
+				t.Errorf("%s %s resolved param %s to %q, expected %q", testCase.method, testCase.path, key, params[testCase.path][key], value)
+			}
+		}
+	}
+}
+
+func TestGetRouteTemplateUnregistered(t *testing.T) {
+	resolved := map[string]string{}
+	params := map[string]map[string]string{}
+	router := newRouteTestRouter(resolved, params)
+
+	// @NOTE Routes must be registered, a stale route list resolves nothing.
+	SetRoutes(nil)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/users/abcd1234", nil))
+
+	if routeTemplate, hasRouteTemplate := resolved["/v1/users/abcd1234"]; hasRouteTemplate {
+		t.Errorf("Unregistered route resolved to %q", routeTemplate)
+	}
+}
+
+func TestExpandRouteTemplate(t *testing.T) {
+	params := gin.Params{
+		gin.Param{Key: "uuid", Value: "abcd1234"},
+		gin.Param{Key: "path", Value: "/docs/rooms.md"},
+	}
+
+	cases := map[string]string{
+		"/v1/users":                 "/v1/users",
+		"/v1/users/:uuid":           "/v1/users/abcd1234",
+		"/v1/users/:uuid/keys/:key": "/v1/users/abcd1234/keys/",
+		"/v1/files/*path":           "/v1/files/docs/rooms.md",
+	}
+
+	for routeTemplate, expected := range cases {
+		if expanded := expandRouteTemplate(routeTemplate, params); expanded != expected {
+			t.Errorf("expandRouteTemplate(%q) returned %q, expected %q", routeTemplate, expanded, expected)
+		}
+	}
+}
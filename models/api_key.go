@@ -0,0 +1,67 @@
+package models
+
+import (
+	// Native packages
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/utils"
+)
+
+/**
+ *	API key for server-to-server clients, only the key hash is stored.
+ *	@NOTE Role caps the role of key owner for requests made with key.
+ */
+type ApiKey struct {
+	ID         int       `json:"-"`
+	UUID       string    `json:"uuid" validate:"required,len=8"`
+	UserId     int       `json:"-"`
+	Name       string    `json:"name" validate:"required,max=255"`
+	Prefix     string    `json:"prefix" validate:"required,len=8"`
+	KeyHash    string    `json:"-" validate:"required,len=64"`
+	Role       int       `json:"role" validate:"required,min=1,max=3"`
+	SafeMode   bool      `json:"safeMode"`
+	MaxLevel   int       `json:"maxLevel"`
+	LastUsedAt null.Time `json:"lastUsedAt"`
+	RevokedAt  null.Time `json:"revokedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+	errors     []string
+}
+
+type ApiKeys []ApiKey
+
+type ApiKeyPayload struct {
+	Name string `json:"name" validate:"required,max=255"`
+	Role int    `json:"role" validate:"omitempty,min=1,max=3"`
+}
+
+/**
+ *	Returns true if key is not revoked.
+ *
+ *	@return bool
+ */
+func (apiKey *ApiKey) IsActive() bool {
+	return !apiKey.RevokedAt.Valid
+}
+
+func (apiKey *ApiKey) Valid() bool {
+	validationError, validationErrors := utils.Validate(apiKey)
+
+	if validationError != nil {
+		apiKey.SetErrors(validationErrors)
+		return false
+	}
+
+	return true
+}
+
+func (apiKey *ApiKey) GetErrors() []string {
+	return apiKey.errors
+}
+
+func (apiKey *ApiKey) SetErrors(errors []string) {
+	apiKey.errors = errors
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	// Native packages
+	"encoding/json"
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+)
+
+/**
+ *	Append-only record of a change, see audit.Record.
+ */
+type AuditEntry struct {
+	ID           int          `json:"-"`
+	ActorId      null.Int     `json:"-"`
+	Actor        *User        `json:"actor" gorm:"-"`
+	Action       string       `json:"action"`
+	ResourceType string       `json:"type"`
+	ResourceUuid string       `json:"resource"`
+	Changes      string       `json:"-"`
+	Diff         AuditChanges `json:"changes" gorm:"-"`
+	CreatedAt    time.Time    `json:"createdAt"`
+}
+
+type AuditEntries []AuditEntry
+
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type AuditChanges map[string]AuditChange
+
+/**
+ *	Decodes stored changes into Diff.
+ *
+ *	@return error
+ */
+func (entry *AuditEntry) Decode() error {
+	return json.Unmarshal([]byte(entry.Changes), &entry.Diff)
+}
@@ -12,21 +12,44 @@ import (
 )
 
 type Category struct {
-	ID        int       `json:"-"`
-	UUID      string    `json:"uuid" validate:"required,len=8"`
-	Name      string    `json:"name"`
-	Slug      string    `json:"slug"`
-	UpdatedAt null.Time `json:"updatedAt"`
-	DeletedAt null.Time `json:"-"`
-	CreatedAt time.Time `json:"createdAt"`
-	errors    []string
+	ID           int       `json:"-"`
+	UUID         string    `json:"uuid" validate:"required,len=8"`
+	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
+	DefaultLevel int       `json:"defaultLevel" validate:"required,min=1,max=4"`
+	UpdatedAt    null.Time `json:"updatedAt"`
+	DeletedAt    null.Time `json:"deletedAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+	errors       []string
 }
 
 type Categories []Category
 
+// @NOTE What happens to live statements when their category is destroyed.
+const CATEGORY_CASCADE_BLOCK = "block"
+const CATEGORY_CASCADE_DELETE = "delete"
+const CATEGORY_CASCADE_REASSIGN = "reassign"
+
 type CategoryPayload struct {
-	Name string `json:"name" validate:"omitempty,gte=3"`
-	Slug string `json:"slug" validate:"omitempty,gte=3"`
+	Name         string `json:"name" validate:"omitempty,gte=3"`
+	Slug         string `json:"slug" validate:"omitempty,gte=3"`
+	DefaultLevel int    `json:"defaultLevel" validate:"omitempty,min=1,max=4"`
+}
+
+// @NOTE Editable properties kept in revisions, see revisions.Record.
+type CategorySnapshot struct {
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DefaultLevel int    `json:"defaultLevel"`
+}
+
+/**
+ *	Returns editable properties of category.
+ *
+ *	@return CategorySnapshot
+ */
+func (category *Category) Snapshot() CategorySnapshot {
+	return CategorySnapshot{Name: category.Name, Slug: category.Slug, DefaultLevel: category.DefaultLevel}
 }
 
 func (category *Category) Valid() bool {
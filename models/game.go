@@ -0,0 +1,99 @@
+package models
+
+import (
+	// Native packages
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/utils"
+)
+
+type Game struct {
+	ID             int        `json:"-"`
+	UUID           string     `json:"uuid" validate:"required,len=8"`
+	Categories     Categories `json:"categories" gorm:"many2many:game_category;"`
+	MaxLevel       null.Int   `json:"maxLevel"`
+	DeckCount      int        `json:"deckCount" gorm:"-"`
+	DrawnCount     int        `json:"drawnCount" gorm:"-"`
+	RemainingCount int        `json:"remainingCount" gorm:"-"`
+	UpdatedAt      null.Time  `json:"updatedAt"`
+	DeletedAt      null.Time  `json:"-"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	errors         []string
+}
+
+type Games []Game
+
+type GamePayload struct {
+	Categories []string `json:"categories" validate:"required,min=1,dive,len=8"`
+	MaxLevel   int      `json:"maxLevel" validate:"omitempty,min=1,max=4"`
+}
+
+type GameStatement struct {
+	ID          int       `json:"-"`
+	GameId      int       `json:"-"`
+	StatementId int       `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type GameStatements []GameStatement
+
+/**
+ *	Returns category ids the game deck is built from.
+ *
+ *	@return []int
+ */
+func (game *Game) GetCategoryIds() []int {
+	var categoryIds []int
+
+	for _, category := range game.Categories {
+		categoryIds = append(categoryIds, category.ID)
+	}
+
+	return categoryIds
+}
+
+/**
+ *	Sets deck counters, deck count is derived from drawn and remaining count.
+ *
+ *	@param drawnCount int
+ *	@param remainingCount int
+ *
+ *	@return void
+ */
+func (game *Game) SetDeckCounts(drawnCount int, remainingCount int) {
+	game.DrawnCount = drawnCount
+	game.RemainingCount = remainingCount
+	game.DeckCount = drawnCount + remainingCount
+}
+
+/**
+ *	Returns true if every statement in the deck has been drawn.
+ *
+ *	@return bool
+ */
+func (game *Game) IsExhausted() bool {
+	return game.RemainingCount == 0
+}
+
+func (game *Game) Valid() bool {
+	validationError, validationErrors := utils.Validate(game)
+
+	if validationError != nil {
+		game.SetErrors(validationErrors)
+		return false
+	}
+
+	return true
+}
+
+func (game *Game) GetErrors() []string {
+	return game.errors
+}
+
+func (game *Game) SetErrors(errors []string) {
+	game.errors = errors
+}
@@ -0,0 +1,27 @@
+package models
+
+const LEVEL_FAMILY = 1
+const LEVEL_MILD = 2
+const LEVEL_SPICY = 3
+const LEVEL_EXPLICIT = 4
+
+/**
+ *	Returns true if level is in range LEVEL_FAMILY to LEVEL_EXPLICIT.
+ *
+ *	@param level int
+ *
+ *	@return bool
+ */
+func IsValidLevel(level int) bool {
+	return level >= LEVEL_FAMILY && level <= LEVEL_EXPLICIT
+}
+
+type Preferences struct {
+	SafeMode bool `json:"safeMode"`
+	MaxLevel int  `json:"maxLevel"`
+}
+
+type PreferencesPayload struct {
+	SafeMode bool `json:"safeMode"`
+	MaxLevel int  `json:"maxLevel" validate:"omitempty,min=1,max=4"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	// Native packages
+	"math"
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+)
+
+const LOGIN_SUBJECT_EMAIL = "email"
+const LOGIN_SUBJECT_IP = "ip"
+
+// @NOTE Failures older than window are forgotten on next failure.
+const LOGIN_FAILURE_WINDOW = time.Hour
+
+// @NOTE Failed attempts allowed before backoff applies, each further failure doubles the wait.
+const LOGIN_BACKOFF_FREE_ATTEMPTS = 3
+const LOGIN_BACKOFF_MAX = 5 * time.Minute
+
+const LOGIN_LOCKOUT_DURATION = 15 * time.Minute
+
+var loginLockoutAttempts = map[string]int{
+	LOGIN_SUBJECT_EMAIL: 10,
+	LOGIN_SUBJECT_IP:    50,
+}
+
+/**
+ *	Failed login attempts of an email address or IP address.
+ */
+type LoginLockout struct {
+	ID            int       `json:"-"`
+	SubjectType   string    `json:"type"`
+	Subject       string    `json:"subject"`
+	FailureCount  int       `json:"failures"`
+	LastFailureAt null.Time `json:"lastFailureAt"`
+	LockedUntil   null.Time `json:"lockedUntil"`
+	Locked        bool      `json:"locked" gorm:"-"`
+	UpdatedAt     null.Time `json:"updatedAt"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type LoginLockouts []LoginLockout
+
+/**
+ *	Returns failed attempts that lock out subject type.
+ *
+ *	@param subjectType string - LOGIN_SUBJECT_EMAIL or LOGIN_SUBJECT_IP.
+ *
+ *	@return int
+ */
+func GetLoginLockoutAttempts(subjectType string) int {
+	return loginLockoutAttempts[subjectType]
+}
+
+/**
+ *	Returns true if subject is locked out at given time.
+ *
+ *	@param now time.Time - Time to check.
+ *
+ *	@return bool
+ */
+func (lockout *LoginLockout) IsLocked(now time.Time) bool {
+	return lockout.LockedUntil.Valid && lockout.LockedUntil.Time.After(now)
+}
+
+/**
+ *	Returns how long subject must wait before next login attempt, 0 if an attempt is allowed now.
+ *
+ *	@param now time.Time - Time to check.
+ *
+ *	@return time.Duration
+ */
+func (lockout *LoginLockout) RetryAfter(now time.Time) time.Duration {
+	if lockout.IsLocked(now) {
+		return lockout.LockedUntil.Time.Sub(now)
+	}
+
+	if !lockout.LastFailureAt.Valid || now.Sub(lockout.LastFailureAt.Time) > LOGIN_FAILURE_WINDOW {
+		return 0
+	}
+
+	excessFailures := lockout.FailureCount - LOGIN_BACKOFF_FREE_ATTEMPTS
+
+	if excessFailures <= 0 {
+		return 0
+	}
+
+	backoff := LOGIN_BACKOFF_MAX
+
+	if excessFailures < 16 {
+		backoff = time.Duration(math.Pow(2, float64(excessFailures-1))) * time.Second
+
+		if backoff > LOGIN_BACKOFF_MAX {
+			backoff = LOGIN_BACKOFF_MAX
+		}
+	}
+
+	nextAttemptAt := lockout.LastFailureAt.Time.Add(backoff)
+
+	if nextAttemptAt.After(now) {
+		return nextAttemptAt.Sub(now)
+	}
+
+	return 0
+}
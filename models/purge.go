@@ -0,0 +1,33 @@
+package models
+
+import (
+	// Native packages
+	"fmt"
+)
+
+/**
+ *	UUIDs of resources removed by a purge run, see purge.Run.
+ */
+type PurgeReport struct {
+	Statements     []string `json:"statements"`
+	Categories     []string `json:"categories"`
+	Users          []string `json:"users"`
+	KeptCategories []string `json:"keptCategories"`
+}
+
+/**
+ *	Returns number of removed resources.
+ *
+ *	@return int
+ */
+func (report PurgeReport) Count() int {
+	return len(report.Statements) + len(report.Categories) + len(report.Users)
+}
+
+func (report PurgeReport) String() string {
+	return fmt.Sprintf("%d statement(s) %v, %d category(ies) %v, %d user(s) %v, kept %d category(ies) with statements %v",
+		len(report.Statements), report.Statements,
+		len(report.Categories), report.Categories,
+		len(report.Users), report.Users,
+		len(report.KeptCategories), report.KeptCategories)
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	// Native packages
+	"encoding/json"
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+)
+
+/**
+ *	Numbered snapshot of a resource's editable properties, see revisions.Record.
+ */
+type Revision struct {
+	ID           int                    `json:"-"`
+	ResourceType string                 `json:"-"`
+	ResourceId   int                    `json:"-"`
+	Number       int                    `json:"number"`
+	UserId       null.Int               `json:"-"`
+	User         *User                  `json:"user" gorm:"-"`
+	RollbackOf   null.Int               `json:"rollbackOf"`
+	Snapshot     string                 `json:"-"`
+	Data         map[string]interface{} `json:"data" gorm:"-"`
+	CreatedAt    time.Time              `json:"createdAt"`
+}
+
+type Revisions []Revision
+
+type RevisionDiff struct {
+	From    int          `json:"from"`
+	To      int          `json:"to"`
+	Changes AuditChanges `json:"changes"`
+}
+
+type RevisionRollbackPayload struct {
+	Revision int `json:"revision" validate:"required,min=1"`
+}
+
+/**
+ *	Decodes stored snapshot into Data.
+ *
+ *	@return error
+ */
+func (revision *Revision) Decode() error {
+	return json.Unmarshal([]byte(revision.Snapshot), &revision.Data)
+}
+
+/**
+ *	Decodes stored snapshot into target, see Statement.Snapshot and Category.Snapshot.
+ *
+ *	@param target interface{} - Snapshot pointer.
+ *
+ *	@return error
+ */
+func (revision *Revision) DecodeInto(target interface{}) error {
+	return json.Unmarshal([]byte(revision.Snapshot), target)
+}
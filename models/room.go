@@ -0,0 +1,197 @@
+package models
+
+import (
+	// Native packages
+	"strings"
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/utils"
+)
+
+const ROOM_ANSWER_HAVE = "have"
+const ROOM_ANSWER_HAVE_NEVER = "haveNever"
+
+type Room struct {
+	ID          int         `json:"-"`
+	UUID        string      `json:"uuid" validate:"required,len=8"`
+	Code        string      `json:"code" validate:"required,len=6"`
+	Game        Game        `json:"game"`
+	GameId      int         `json:"-"`
+	Statement   *Statement  `json:"statement" gorm:"-"`
+	StatementId null.Int    `json:"-"`
+	Players     RoomPlayers `json:"players" gorm:"ForeignKey:RoomId"`
+	UpdatedAt   null.Time   `json:"updatedAt"`
+	DeletedAt   null.Time   `json:"-"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	errors      []string
+}
+
+type Rooms []Room
+
+type RoomPayload struct {
+	Game string `json:"game" validate:"required,len=8"`
+}
+
+type RoomPlayer struct {
+	ID             int       `json:"-"`
+	UUID           string    `json:"uuid" validate:"required,len=8"`
+	RoomId         int       `json:"-"`
+	UserId         null.Int  `json:"-"`
+	Name           string    `json:"name" validate:"required,gte=1,lte=32"`
+	Token          string    `json:"-" validate:"required,len=16"`
+	HaveCount      int       `json:"haveCount"`
+	HaveNeverCount int       `json:"haveNeverCount"`
+	UpdatedAt      null.Time `json:"updatedAt"`
+	DeletedAt      null.Time `json:"-"`
+	CreatedAt      time.Time `json:"createdAt"`
+	errors         []string
+}
+
+type RoomPlayers []RoomPlayer
+
+type RoomPlayerPayload struct {
+	Name string `json:"name" validate:"required,gte=1,lte=32"`
+}
+
+type RoomPlayerCredentials struct {
+	Player string `json:"player" validate:"required,len=8"`
+	Token  string `json:"token" validate:"required,len=16"`
+}
+
+type RoomAnswer struct {
+	ID           int       `json:"-"`
+	RoomId       int       `json:"-"`
+	RoomPlayerId int       `json:"-"`
+	StatementId  int       `json:"-"`
+	Answer       string    `json:"answer"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type RoomAnswers []RoomAnswer
+
+type RoomAnswerPayload struct {
+	Player string `json:"player" validate:"required,len=8"`
+	Token  string `json:"token" validate:"required,len=16"`
+	Answer string `json:"answer" validate:"required,eq=have|eq=haveNever"`
+}
+
+type RoomSocketMessage struct {
+	Action string `json:"action"`
+	Name   string `json:"name"`
+	Player string `json:"player"`
+	Token  string `json:"token"`
+	Answer string `json:"answer"`
+}
+
+/**
+ *	Returns event topic used for room updates.
+ *
+ *	@return string
+ */
+func (room *Room) GetTopic() string {
+	return "room:" + room.Code
+}
+
+/**
+ *	Returns player in room roster matching player UUID and token.
+ *
+ *	@param playerId string - Player UUID.
+ *	@param playerToken string - Player token, handed out when joining.
+ *
+ *	@return RoomPlayer, bool
+ */
+func (room *Room) FindPlayer(playerId string, playerToken string) (RoomPlayer, bool) {
+	for _, player := range room.Players {
+		if player.UUID == playerId && player.Token == playerToken {
+			return player, true
+		}
+	}
+
+	return RoomPlayer{}, false
+}
+
+/**
+ *	Returns true if room roster has a player with specified name, names are compared case insensitively like the room_name key.
+ *
+ *	@param playerName string
+ *
+ *	@return bool
+ */
+func (room *Room) HasPlayerName(playerName string) bool {
+	for _, player := range room.Players {
+		if strings.EqualFold(player.Name, playerName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (room *Room) Valid() bool {
+	validationError, validationErrors := utils.Validate(room)
+
+	if validationError != nil {
+		room.SetErrors(validationErrors)
+		return false
+	}
+
+	return true
+}
+
+func (room *Room) GetErrors() []string {
+	return room.errors
+}
+
+func (room *Room) SetErrors(errors []string) {
+	room.errors = errors
+}
+
+/**
+ *	Returns true if player is not bound to a user account.
+ *
+ *	@return bool
+ */
+func (player *RoomPlayer) IsGuest() bool {
+	return !player.UserId.Valid
+}
+
+/**
+ *	Adds answer to player tally.
+ *
+ *	@param answer string - Either ROOM_ANSWER_HAVE or ROOM_ANSWER_HAVE_NEVER.
+ *
+ *	@return void
+ */
+func (player *RoomPlayer) Tally(answer string) {
+	switch answer {
+	case ROOM_ANSWER_HAVE:
+		player.HaveCount++
+		break
+	case ROOM_ANSWER_HAVE_NEVER:
+		player.HaveNeverCount++
+		break
+	}
+}
+
+func (player *RoomPlayer) Valid() bool {
+	validationError, validationErrors := utils.Validate(player)
+
+	if validationError != nil {
+		player.SetErrors(validationErrors)
+		return false
+	}
+
+	return true
+}
+
+func (player *RoomPlayer) GetErrors() []string {
+	return player.errors
+}
+
+func (player *RoomPlayer) SetErrors(errors []string) {
+	player.errors = errors
+}
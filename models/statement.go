@@ -12,22 +12,170 @@ import (
 )
 
 type Statement struct {
-	ID         int       `json:"-"`
-	UUID       string    `json:"uuid" validate:"required,len=8"`
-	Body       string    `json:"body"`
-	Category   Category  `json:"category"`
-	CategoryId int       `json:"-"`
-	UpdatedAt  null.Time `json:"updatedAt"`
-	DeletedAt  null.Time `json:"-"`
-	CreatedAt  time.Time `json:"createdAt"`
-	errors     []string
+	ID                  int                   `json:"-"`
+	UUID                string                `json:"uuid" validate:"required,len=8"`
+	Body                string                `json:"body"`
+	Status              string                `json:"status"`
+	Level               null.Int              `json:"level"`
+	Upvotes             int                   `json:"upvotes" gorm:"column:upvote_count"`
+	Downvotes           int                   `json:"downvotes" gorm:"column:downvote_count"`
+	Favourites          int                   `json:"favourites" gorm:"column:favourite_count"`
+	UserId              null.Int              `json:"-"`
+	Language            string                `json:"language,omitempty" gorm:"-"`
+	Highlight           string                `json:"highlight,omitempty" gorm:"-"`
+	Translations        StatementTranslations `json:"-" gorm:"ForeignKey:StatementId"`
+	Category            Category              `json:"category"`
+	CategoryId          int                   `json:"-"`
+	UpdatedAt           null.Time             `json:"updatedAt"`
+	DeletedAt           null.Time             `json:"deletedAt"`
+	DeletedWithCategory bool                  `json:"-"`
+	CreatedAt           time.Time             `json:"createdAt"`
+	errors              []string
 }
 
 type Statements []Statement
 
+// @NOTE New statements are created as drafts, "status" column defaults to published so statements created before moderation stay listed.
+const STATEMENT_STATUS_DRAFT = "draft"
+const STATEMENT_STATUS_PENDING = "pending"
+const STATEMENT_STATUS_PUBLISHED = "published"
+const STATEMENT_STATUS_REJECTED = "rejected"
+
+// @NOTE Maps current status to statuses it may transition to.
+var statementTransitions = map[string][]string{
+	STATEMENT_STATUS_DRAFT:     {STATEMENT_STATUS_PENDING},
+	STATEMENT_STATUS_PENDING:   {STATEMENT_STATUS_PUBLISHED, STATEMENT_STATUS_REJECTED, STATEMENT_STATUS_DRAFT},
+	STATEMENT_STATUS_PUBLISHED: {STATEMENT_STATUS_PENDING},
+	STATEMENT_STATUS_REJECTED:  {STATEMENT_STATUS_DRAFT},
+}
+
+type StatementTransitionPayload struct {
+	Status string `json:"status" validate:"required,eq=draft|eq=pending|eq=published|eq=rejected"`
+	Reason string `json:"reason" validate:"omitempty,lte=1000"`
+}
+
+type StatementModeration struct {
+	ID          int       `json:"-"`
+	StatementId int       `json:"-"`
+	UserId      null.Int  `json:"-"`
+	FromStatus  string    `json:"fromStatus"`
+	ToStatus    string    `json:"toStatus"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type StatementModerations []StatementModeration
+
+const STATEMENT_EDIT_REASON = "Edited after publishing."
+
+const STATEMENT_DUPLICATE_THRESHOLD = 0.8
+const STATEMENT_DUPLICATE_CANDIDATES = 50
+
+type StatementDuplicate struct {
+	Statement  Statement `json:"statement"`
+	Similarity float64   `json:"similarity"`
+}
+
+type StatementDuplicates []StatementDuplicate
+
 type StatementPayload struct {
 	Body     string `json:"body" validate:"omitempty,gte=3"`
 	Category string `json:"category" validate:"omitempty,len=8"`
+	Level    int    `json:"level" validate:"omitempty,min=1,max=4"`
+}
+
+// @NOTE Editable properties kept in revisions, see revisions.Record. Category is kept by UUID, empty in revisions recorded before it was kept.
+type StatementSnapshot struct {
+	Body     string   `json:"body"`
+	Category string   `json:"category"`
+	Level    null.Int `json:"level"`
+}
+
+/**
+ *	Returns editable properties of statement, Category has to be loaded.
+ *
+ *	@return StatementSnapshot
+ */
+func (statement *Statement) Snapshot() StatementSnapshot {
+	return StatementSnapshot{Body: statement.Body, Category: statement.Category.UUID, Level: statement.Level}
+}
+
+/**
+ *	Returns statement level, falls back to category default level when not set.
+ *
+ *	@return int
+ */
+func (statement *Statement) GetLevel() int {
+	if statement.Level.Valid {
+		return int(statement.Level.Int64)
+	}
+
+	return statement.Category.DefaultLevel
+}
+
+/**
+ *	Returns true if statement may transition from its current status to new status.
+ *
+ *	@param newStatus string
+ *
+ *	@return bool
+ */
+func (statement *Statement) CanTransition(newStatus string) bool {
+	for _, allowedStatus := range statementTransitions[statement.Status] {
+		if allowedStatus == newStatus {
+			return true
+		}
+	}
+
+	return false
+}
+
+/**
+ *	Returns true if statement owner may make the transition without a moderator, owners may only submit drafts for review.
+ *
+ *	@param newStatus string
+ *
+ *	@return bool
+ */
+func (statement *Statement) CanOwnerTransition(newStatus string) bool {
+	return statement.Status == STATEMENT_STATUS_DRAFT && newStatus == STATEMENT_STATUS_PENDING
+}
+
+/**
+ *	Returns true if transitioning to status is a moderation decision, only moderators may decide.
+ *
+ *	@param newStatus string
+ *
+ *	@return bool
+ */
+func IsStatementDecision(newStatus string) bool {
+	return newStatus == STATEMENT_STATUS_PUBLISHED || newStatus == STATEMENT_STATUS_REJECTED
+}
+
+/**
+ *	Resolves body from preloaded translations using first matching language in fallback chain.
+ *
+ *	@param languages []string - Language fallback chain, see utils.LanguageFallbacks.
+ *	@param baseLanguage string - Language of untranslated body.
+ *
+ *	@return void
+ */
+func (statement *Statement) Localize(languages []string, baseLanguage string) {
+	for _, language := range languages {
+		if language == baseLanguage {
+			break
+		}
+
+		for _, translation := range statement.Translations {
+			if translation.Language == language {
+				statement.Body = translation.Body
+				statement.Language = translation.Language
+				return
+			}
+		}
+	}
+
+	statement.Language = baseLanguage
 }
 
 func (statement *Statement) Valid() bool {
@@ -0,0 +1,76 @@
+package models
+
+const STATEMENT_BATCH_MODE_ATOMIC = "atomic"
+const STATEMENT_BATCH_MODE_PARTIAL = "partial"
+
+// @NOTE Atomic batches are rolled back if any item fails, partial batches keep items that succeed.
+type StatementBatchCreatePayload struct {
+	Mode  string             `json:"mode" validate:"omitempty,eq=atomic|eq=partial"`
+	Items []StatementPayload `json:"items" validate:"required,min=1,max=500"`
+}
+
+type StatementBatchUpdate struct {
+	UUID string `json:"uuid" validate:"required,len=8"`
+	StatementPayload
+}
+
+type StatementBatchUpdatePayload struct {
+	Mode  string                 `json:"mode" validate:"omitempty,eq=atomic|eq=partial"`
+	Items []StatementBatchUpdate `json:"items" validate:"required,min=1,max=500"`
+}
+
+type StatementBatchReference struct {
+	UUID string `json:"uuid" validate:"required,len=8"`
+}
+
+type StatementBatchDestroyPayload struct {
+	Mode  string                    `json:"mode" validate:"omitempty,eq=atomic|eq=partial"`
+	Items []StatementBatchReference `json:"items" validate:"required,min=1,max=500"`
+}
+
+/**
+ *	Outcome of a single batch item, Status is the HTTP status the item would get on its own.
+ */
+type StatementBatchResult struct {
+	Index      int                 `json:"index"`
+	Status     int                 `json:"status"`
+	Error      string              `json:"error,omitempty"`
+	Issues     []string            `json:"issues,omitempty"`
+	Duplicates StatementDuplicates `json:"duplicates,omitempty"`
+	Statement  *Statement          `json:"statement,omitempty"`
+}
+
+type StatementBatchResults []StatementBatchResult
+
+type StatementBatchResponse struct {
+	Mode      string                `json:"mode"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   StatementBatchResults `json:"results"`
+}
+
+/**
+ *	Returns true if item failed.
+ *
+ *	@return bool
+ */
+func (result StatementBatchResult) Failed() bool {
+	return result.Status >= 400
+}
+
+/**
+ *	Returns highest HTTP status of failed items, 0 if no item failed.
+ *
+ *	@return int
+ */
+func (results StatementBatchResults) WorstStatus() int {
+	worstStatus := 0
+
+	for _, result := range results {
+		if result.Failed() && result.Status > worstStatus {
+			worstStatus = result.Status
+		}
+	}
+
+	return worstStatus
+}
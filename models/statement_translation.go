@@ -0,0 +1,49 @@
+package models
+
+import (
+	// Native packages
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/utils"
+)
+
+type StatementTranslation struct {
+	ID          int       `json:"-"`
+	StatementId int       `json:"-"`
+	Language    string    `json:"language" validate:"required,gte=2,lte=8"`
+	Body        string    `json:"body" validate:"required,gte=3"`
+	UpdatedAt   null.Time `json:"updatedAt"`
+	DeletedAt   null.Time `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+	errors      []string
+}
+
+type StatementTranslations []StatementTranslation
+
+type StatementTranslationPayload struct {
+	Language string `json:"language" validate:"omitempty,gte=2,lte=8"`
+	Body     string `json:"body" validate:"omitempty,gte=3"`
+}
+
+func (translation *StatementTranslation) Valid() bool {
+	validationError, validationErrors := utils.Validate(translation)
+
+	if validationError != nil {
+		translation.SetErrors(validationErrors)
+		return false
+	}
+
+	return true
+}
+
+func (translation *StatementTranslation) GetErrors() []string {
+	return translation.errors
+}
+
+func (translation *StatementTranslation) SetErrors(errors []string) {
+	translation.errors = errors
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	// Native packages
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+)
+
+type RefreshToken struct {
+	ID        int       `json:"-"`
+	UserId    int       `json:"-"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	RevokedAt null.Time `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type RevokedToken struct {
+	ID        int       `json:"-"`
+	Jti       string    `json:"-"`
+	UserId    int       `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+	CreatedAt time.Time `json:"-"`
+}
+
+type LoginPayload struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type LogoutPayload struct {
+	RefreshToken string `json:"refreshToken"`
+	All          bool   `json:"all"`
+}
+
+/**
+ *	Returns true if refresh token is neither revoked nor expired.
+ *
+ *	@return bool
+ */
+func (token *RefreshToken) IsActive() bool {
+	return !token.RevokedAt.Valid && token.ExpiresAt.After(time.Now())
+}
@@ -16,18 +16,23 @@ const USER_ROLE_MOD = 2
 const USER_ROLE_ADMIN = 3
 
 type User struct {
-	ID        int       `json:"-"`
-	UUID      string    `json:"uuid" validate:"required,len=8"`
-	FirstName string    `json:"firstName" validate:"required"`
-	LastName  string    `json:"lastName" validate:"required"`
-	Email     string    `json:"email" validate:"required,email"`
-	Password  string    `json:"-"`
-	AuthKey   string    `json:"-" validate:"required,len=16`
-	Role      int       `json:"-"`
-	UpdatedAt null.Time `json:"updatedAt"`
-	DeletedAt null.Time `json:"-"`
-	CreatedAt time.Time `json:"createdAt"`
-	errors    []string
+	ID               int         `json:"-"`
+	UUID             string      `json:"uuid" validate:"required,len=8"`
+	FirstName        string      `json:"firstName" validate:"required"`
+	LastName         string      `json:"lastName" validate:"required"`
+	Email            string      `json:"email" validate:"required,email"`
+	Password         string      `json:"-"`
+	AuthKey          string      `json:"-" validate:"required,len=16"`
+	Role             int         `json:"role"`
+	SafeMode         bool        `json:"-"`
+	MaxLevel         int         `json:"-"`
+	VerifiedAt       null.Time   `json:"verifiedAt"`
+	SuspendedAt      null.Time   `json:"suspendedAt"`
+	SuspensionReason null.String `json:"suspensionReason"`
+	UpdatedAt        null.Time   `json:"updatedAt"`
+	DeletedAt        null.Time   `json:"deletedAt"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	errors           []string
 }
 
 type Users []User
@@ -40,6 +45,80 @@ type UserPayload struct {
 	PasswordConfirm string `json:"passwordConfirm" validate:"omitempty,gte=6"`
 }
 
+type UserRolePayload struct {
+	Role int `json:"role" validate:"required,min=1,max=3"`
+}
+
+type UserSuspensionPayload struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type MePayload struct {
+	FirstName string `json:"firstName" validate:"omitempty,gte=3"`
+	LastName  string `json:"lastName" validate:"omitempty,gte=3"`
+	Email     string `json:"email" validate:"omitempty,email"`
+}
+
+type PasswordChangePayload struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	Password        string `json:"password" validate:"required,eqfield=PasswordConfirm"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"required,gte=6"`
+}
+
+/**
+ *	Returns true if user role is USER_ROLE_MOD or higher.
+ *
+ *	@return bool
+ */
+func (user *User) IsModerator() bool {
+	return user.Role >= USER_ROLE_MOD
+}
+
+/**
+ *	Returns true if user role is USER_ROLE_ADMIN.
+ *
+ *	@return bool
+ */
+func (user *User) IsAdmin() bool {
+	return user.Role == USER_ROLE_ADMIN
+}
+
+/**
+ *	Returns true if user is suspended by an administrator.
+ *
+ *	@return bool
+ */
+func (user *User) IsSuspended() bool {
+	return user.SuspendedAt.Valid
+}
+
+/**
+ *	Returns true if user may be issued tokens or use existing ones.
+ *
+ *	@return bool
+ */
+func (user *User) CanAuthenticate() bool {
+	return user.ID != 0 && user.AuthKey != "" && !user.IsSuspended()
+}
+
+/**
+ *	Returns true if user has verified email address.
+ *
+ *	@return bool
+ */
+func (user *User) IsVerified() bool {
+	return user.VerifiedAt.Valid
+}
+
+/**
+ *	Returns short hash of user auth key, embedded in access tokens so that rotating the key invalidates them.
+ *
+ *	@return string
+ */
+func (user *User) GetAuthKeyHash() string {
+	return utils.HashToken(user.AuthKey)[:16]
+}
+
 func (user *User) Valid() bool {
 	validationError, validationErrors := utils.Validate(user)
 
@@ -0,0 +1,24 @@
+package models
+
+import (
+	// Native packages
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+)
+
+/**
+ *	External identity linked to a user, subject is unique per provider.
+ */
+type UserIdentity struct {
+	ID          int       `json:"-"`
+	UserId      int       `json:"-"`
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"-"`
+	Email       string    `json:"email"`
+	LastLoginAt null.Time `json:"lastLoginAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type UserIdentities []UserIdentity
@@ -0,0 +1,48 @@
+package models
+
+import (
+	// Native packages
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+)
+
+const USER_TOKEN_PURPOSE_RESET = "reset"
+const USER_TOKEN_PURPOSE_VERIFY = "verify"
+
+/**
+ *	Single-use token sent to a user by mail, only the token hash is stored.
+ */
+type UserToken struct {
+	ID        int       `json:"-"`
+	UserId    int       `json:"-"`
+	Purpose   string    `json:"-"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+	UsedAt    null.Time `json:"-"`
+	CreatedAt time.Time `json:"-"`
+}
+
+type ForgotPasswordPayload struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordPayload struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required,eqfield=PasswordConfirm"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"required,gte=6"`
+}
+
+type VerifyEmailPayload struct {
+	Token string `json:"token" validate:"required"`
+}
+
+/**
+ *	Returns true if token is neither used nor expired.
+ *
+ *	@return bool
+ */
+func (token *UserToken) IsActive() bool {
+	return !token.UsedAt.Valid && token.ExpiresAt.After(time.Now())
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	// Native packages
+	"time"
+
+	// 3rd party packages
+	"gopkg.in/guregu/null.v3"
+)
+
+const VOTE_UP = 1
+const VOTE_DOWN = -1
+
+type StatementVote struct {
+	ID          int       `json:"-"`
+	StatementId int       `json:"-"`
+	UserId      int       `json:"-"`
+	Value       int       `json:"value"`
+	UpdatedAt   null.Time `json:"updatedAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type StatementVotes []StatementVote
+
+type StatementVotePayload struct {
+	Value int `json:"value" validate:"required,eq=1|eq=-1"`
+}
+
+type StatementFavourite struct {
+	ID          int       `json:"-"`
+	StatementId int       `json:"-"`
+	Statement   Statement `json:"statement"`
+	UserId      int       `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type StatementFavourites []StatementFavourite
+
+/**
+ *	Returns upvote and downvote count changes caused by replacing a vote.
+ *
+ *	@param previousValue int - Previous vote value, 0 if there was no vote.
+ *	@param newValue int - New vote value, 0 if vote is removed.
+ *
+ *	@return int, int
+ */
+func VoteCountDeltas(previousValue int, newValue int) (int, int) {
+	upvoteDelta, downvoteDelta := 0, 0
+
+	switch previousValue {
+	case VOTE_UP:
+		upvoteDelta--
+		break
+	case VOTE_DOWN:
+		downvoteDelta--
+		break
+	}
+
+	switch newValue {
+	case VOTE_UP:
+		upvoteDelta++
+		break
+	case VOTE_DOWN:
+		downvoteDelta++
+		break
+	}
+
+	return upvoteDelta, downvoteDelta
+}
@@ -0,0 +1,135 @@
+package oidc
+
+import (
+	// Native packages
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// @NOTE Allowed clock difference between provider and API.
+const CLOCK_SKEW = time.Minute
+
+/**
+ *	ID token claims used to identify and provision users.
+ */
+type Claims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      Audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified Flag     `json:"email_verified"`
+	Name          string   `json:"name"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+}
+
+/**
+ *	Audience claim, either a single string or an array of strings.
+ */
+type Audience []string
+
+/**
+ *	Boolean claim, some providers send booleans as strings.
+ */
+type Flag bool
+
+func (audience *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+
+	if json.Unmarshal(data, &single) == nil {
+		*audience = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+
+	if unmarshalError := json.Unmarshal(data, &multiple); unmarshalError != nil {
+		return unmarshalError
+	}
+
+	*audience = Audience(multiple)
+	return nil
+}
+
+func (flag *Flag) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), "\"")
+	*flag = Flag(value == "true")
+	return nil
+}
+
+/**
+ *	Returns claims of an ID token without verifying its signature.
+ *	@NOTE Only use with ID tokens received directly from token endpoint, TLS then authenticates the issuer, see OpenID Connect Core 1.0 section 3.1.3.7.
+ *
+ *	@param idToken string - Compact serialized ID token.
+ *
+ *	@return Claims, error
+ */
+func ParseIdToken(idToken string) (Claims, error) {
+	var claims Claims
+
+	tokenParts := strings.Split(idToken, ".")
+
+	if len(tokenParts) != 3 {
+		return claims, errors.New("OIDC ID token is malformed.")
+	}
+
+	payload, decodeError := base64.RawURLEncoding.DecodeString(strings.TrimRight(tokenParts[1], "="))
+
+	if decodeError != nil {
+		return claims, decodeError
+	}
+
+	if unmarshalError := json.Unmarshal(payload, &claims); unmarshalError != nil {
+		return claims, unmarshalError
+	}
+
+	return claims, nil
+}
+
+/**
+ *	Validates issuer, audience, expiry and nonce of claims.
+ *
+ *	@param issuer string - Expected issuer.
+ *	@param clientId string - Client ID, must be in audience.
+ *	@param nonce string - Nonce sent in authorization request.
+ *	@param now time.Time - Current time.
+ *
+ *	@return error
+ */
+func (claims Claims) Validate(issuer string, clientId string, nonce string, now time.Time) error {
+	if strings.TrimRight(claims.Issuer, "/") != strings.TrimRight(issuer, "/") {
+		return errors.New("OIDC ID token issuer does not match.")
+	}
+
+	hasAudience := false
+
+	for _, audience := range claims.Audience {
+		if audience == clientId {
+			hasAudience = true
+		}
+	}
+
+	if !hasAudience {
+		return errors.New("OIDC ID token is not issued for this client.")
+	}
+
+	if now.Add(-CLOCK_SKEW).Unix() > claims.ExpiresAt {
+		return errors.New("OIDC ID token is expired.")
+	}
+
+	if claims.Nonce != nonce {
+		return errors.New("OIDC ID token nonce does not match.")
+	}
+
+	if claims.Subject == "" {
+		return errors.New("OIDC ID token has no subject.")
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package oidc
+
+import (
+	// Native packages
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	// Local packages
+	"jaha-api/env"
+	"jaha-api/utils"
+)
+
+const HTTP_TIMEOUT = 10 * time.Second
+const DISCOVERY_TTL = time.Hour
+const STATE_LENGTH = 32
+const NONCE_LENGTH = 32
+
+// @NOTE RFC 7636 requires 43 to 128 characters from the unreserved set.
+const CODE_VERIFIER_LENGTH = 64
+const CODE_VERIFIER_CHARS = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-._~"
+
+var ErrNotConfigured = errors.New("OIDC provider is not configured.")
+
+/**
+ *	OpenID provider metadata, see OpenID Connect Discovery 1.0.
+ */
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+/**
+ *	Authorization request state, kept by client between redirect and callback.
+ */
+type Flow struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+type Provider struct {
+	Name         string
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string
+	Scopes       []string
+	HttpClient   *http.Client
+	discovery    *Discovery
+	discoveredAt time.Time
+	lock         sync.Mutex
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IdToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+var defaultProvider *Provider
+var defaultProviderOnce sync.Once
+
+/**
+ *	Returns provider configured by OIDC_* environment variables, nil if OIDC_ISSUER or OIDC_CLIENT_ID is not set.
+ *
+ *	@return *Provider
+ */
+func GetProvider() *Provider {
+	defaultProviderOnce.Do(func() {
+		if env.GetOidcIssuer() != "" && env.GetOidcClientId() != "" {
+			defaultProvider = NewProvider(
+				env.GetOidcProviderName(),
+				env.GetOidcIssuer(),
+				env.GetOidcClientId(),
+				env.GetOidcClientSecret(),
+				env.GetOidcRedirectUrl(),
+				env.GetOidcScopes(),
+			)
+		}
+	})
+
+	return defaultProvider
+}
+
+/**
+ *	Returns new provider.
+ *
+ *	@param name string - Provider name, stored with linked identities.
+ *	@param issuer string - Issuer URL, metadata is discovered from it.
+ *	@param clientId string - Client ID.
+ *	@param clientSecret string - Client secret, empty for public clients.
+ *	@param redirectUrl string - Callback URL registered with provider.
+ *	@param scopes []string - Requested scopes, "openid" is always included.
+ *
+ *	@return *Provider
+ */
+func NewProvider(name string, issuer string, clientId string, clientSecret string, redirectUrl string, scopes []string) *Provider {
+	hasOpenId := false
+
+	for _, scope := range scopes {
+		if scope == "openid" {
+			hasOpenId = true
+		}
+	}
+
+	if !hasOpenId {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	return &Provider{
+		Name:         name,
+		Issuer:       strings.TrimRight(issuer, "/"),
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		RedirectUrl:  redirectUrl,
+		Scopes:       scopes,
+		HttpClient:   &http.Client{Timeout: HTTP_TIMEOUT},
+	}
+}
+
+/**
+ *	Returns new flow with random state, nonce and PKCE code verifier.
+ *
+ *	@return Flow
+ */
+func NewFlow() Flow {
+	return Flow{
+		State:        utils.RandomString(STATE_LENGTH),
+		Nonce:        utils.RandomString(NONCE_LENGTH),
+		CodeVerifier: utils.RandomStringFrom(CODE_VERIFIER_CHARS, CODE_VERIFIER_LENGTH),
+	}
+}
+
+/**
+ *	Returns S256 PKCE code challenge of code verifier.
+ *
+ *	@param codeVerifier string - PKCE code verifier.
+ *
+ *	@return string
+ */
+func CodeChallenge(codeVerifier string) string {
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(challenge[:])
+}
+
+/**
+ *	Returns provider metadata, cached for DISCOVERY_TTL.
+ *
+ *	@return *Discovery, error
+ */
+func (provider *Provider) Discover() (*Discovery, error) {
+	var discovery Discovery
+
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+
+	if provider.discovery != nil && time.Since(provider.discoveredAt) < DISCOVERY_TTL {
+		return provider.discovery, nil
+	}
+
+	response, requestError := provider.HttpClient.Get(provider.Issuer + "/.well-known/openid-configuration")
+
+	if requestError != nil {
+		return nil, requestError
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OIDC discovery failed with status %d.", response.StatusCode)
+	}
+
+	if decodeError := json.NewDecoder(response.Body).Decode(&discovery); decodeError != nil {
+		return nil, decodeError
+	}
+
+	if strings.TrimRight(discovery.Issuer, "/") != provider.Issuer {
+		return nil, fmt.Errorf("OIDC discovery issuer %s does not match %s.", discovery.Issuer, provider.Issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" {
+		return nil, errors.New("OIDC discovery is missing endpoints.")
+	}
+
+	provider.discovery = &discovery
+	provider.discoveredAt = time.Now()
+
+	return provider.discovery, nil
+}
+
+/**
+ *	Returns provider authorization URL for flow.
+ *
+ *	@param flow Flow - Authorization request state.
+ *
+ *	@return string, error
+ */
+func (provider *Provider) AuthorizationUrl(flow Flow) (string, error) {
+	discovery, discoveryError := provider.Discover()
+
+	if discoveryError != nil {
+		return "", discoveryError
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.ClientId)
+	query.Set("redirect_uri", provider.RedirectUrl)
+	query.Set("scope", strings.Join(provider.Scopes, " "))
+	query.Set("state", flow.State)
+	query.Set("nonce", flow.Nonce)
+	query.Set("code_challenge", CodeChallenge(flow.CodeVerifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+/**
+ *	Exchanges authorization code for tokens and returns validated ID token claims.
+ *
+ *	@param code string - Authorization code from callback.
+ *	@param flow Flow - Authorization request state.
+ *
+ *	@return Claims, error
+ */
+func (provider *Provider) Exchange(code string, flow Flow) (Claims, error) {
+	var tokens tokenResponse
+
+	discovery, discoveryError := provider.Discover()
+
+	if discoveryError != nil {
+		return Claims{}, discoveryError
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.RedirectUrl)
+	form.Set("client_id", provider.ClientId)
+	form.Set("code_verifier", flow.CodeVerifier)
+
+	request, requestError := http.NewRequest("POST", discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+
+	if requestError != nil {
+		return Claims{}, requestError
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	if provider.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(provider.ClientId), url.QueryEscape(provider.ClientSecret))
+	}
+
+	response, responseError := provider.HttpClient.Do(request)
+
+	if responseError != nil {
+		return Claims{}, responseError
+	}
+
+	defer response.Body.Close()
+
+	if decodeError := json.NewDecoder(response.Body).Decode(&tokens); decodeError != nil {
+		return Claims{}, decodeError
+	}
+
+	if response.StatusCode != http.StatusOK || tokens.Error != "" {
+		return Claims{}, fmt.Errorf("OIDC token request failed: %s %s", tokens.Error, tokens.ErrorDescription)
+	}
+
+	if tokens.IdToken == "" {
+		return Claims{}, errors.New("OIDC token response has no ID token.")
+	}
+
+	claims, parseError := ParseIdToken(tokens.IdToken)
+
+	if parseError != nil {
+		return Claims{}, parseError
+	}
+
+	if validationError := claims.Validate(provider.Issuer, provider.ClientId, flow.Nonce, time.Now()); validationError != nil {
+		return Claims{}, validationError
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,95 @@
+package oidc_test
+
+import (
+	// Native packages
+	"strings"
+	"testing"
+
+	// Local packages
+	"jaha-api/oidc"
+	"jaha-api/oidc/oidctest"
+)
+
+const testRedirectUrl = "http://localhost:4000/v1/auth/oidc/callback"
+
+func TestExchange(t *testing.T) {
+	server := oidctest.NewServer("client", "secret")
+	defer server.Close()
+
+	flow := oidc.NewFlow()
+	server.Claims = oidc.Claims{Subject: "subject", Nonce: flow.Nonce, Email: "jane@example.com", EmailVerified: true}
+
+	claims, exchangeError := server.Provider(testRedirectUrl).Exchange(server.Code, flow)
+
+	if exchangeError != nil {
+		t.Fatalf("Exchange failed: %s", exchangeError)
+	}
+
+	if claims.Subject != "subject" || claims.Email != "jane@example.com" || !bool(claims.EmailVerified) {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	tokenRequest := server.LastTokenRequest()
+
+	if tokenRequest.Get("code_verifier") != flow.CodeVerifier {
+		t.Errorf("Token request sent code verifier %q, expected %q", tokenRequest.Get("code_verifier"), flow.CodeVerifier)
+	}
+
+	if tokenRequest.Get("redirect_uri") != testRedirectUrl {
+		t.Errorf("Token request sent redirect URI %q", tokenRequest.Get("redirect_uri"))
+	}
+}
+
+func TestExchangeRejectsNonce(t *testing.T) {
+	server := oidctest.NewServer("client", "")
+	defer server.Close()
+
+	flow := oidc.NewFlow()
+	server.Claims = oidc.Claims{Subject: "subject", Nonce: "replayed"}
+
+	if _, exchangeError := server.Provider(testRedirectUrl).Exchange(server.Code, flow); exchangeError == nil {
+		t.Error("Exchange accepted ID token with another nonce")
+	}
+}
+
+func TestExchangeRejectsExpiredToken(t *testing.T) {
+	server := oidctest.NewServer("client", "")
+	defer server.Close()
+
+	flow := oidc.NewFlow()
+	server.Claims = oidc.Claims{Subject: "subject", Nonce: flow.Nonce, ExpiresAt: 1}
+
+	if _, exchangeError := server.Provider(testRedirectUrl).Exchange(server.Code, flow); exchangeError == nil {
+		t.Error("Exchange accepted expired ID token")
+	}
+}
+
+func TestExchangeRejectsInvalidCode(t *testing.T) {
+	server := oidctest.NewServer("client", "secret")
+	defer server.Close()
+
+	flow := oidc.NewFlow()
+	server.Claims = oidc.Claims{Subject: "subject", Nonce: flow.Nonce}
+
+	if _, exchangeError := server.Provider(testRedirectUrl).Exchange("stolen", flow); exchangeError == nil {
+		t.Error("Exchange accepted invalid authorization code")
+	}
+}
+
+func TestAuthorizationUrl(t *testing.T) {
+	server := oidctest.NewServer("client", "")
+	defer server.Close()
+
+	flow := oidc.NewFlow()
+	authorizationUrl, urlError := server.Provider(testRedirectUrl).AuthorizationUrl(flow)
+
+	if urlError != nil {
+		t.Fatalf("AuthorizationUrl failed: %s", urlError)
+	}
+
+	for _, expected := range []string{server.URL + "/authorize?", "code_challenge=" + oidc.CodeChallenge(flow.CodeVerifier), "nonce=" + flow.Nonce, "state=" + flow.State} {
+		if !strings.Contains(authorizationUrl, expected) {
+			t.Errorf("Authorization URL %s does not contain %s", authorizationUrl, expected)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package oidctest
+
+import (
+	// Native packages
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	// Local packages
+	"jaha-api/oidc"
+)
+
+/**
+ *	Local OpenID provider serving discovery and token endpoints, used to test login flows without a real provider.
+ *	@NOTE ID tokens are not signed, claims are only trusted because they come from token endpoint, see oidc.ParseIdToken.
+ */
+type Server struct {
+	*httptest.Server
+	ClientId     string
+	ClientSecret string
+	Code         string
+	Claims       oidc.Claims
+	lastForm     url.Values
+	lock         sync.Mutex
+}
+
+/**
+ *	Returns started server, close it with Close.
+ *	Token endpoint accepts Code once per request and answers with Claims, issuer, audience and expiry are filled in.
+ *
+ *	@param clientId string - Client ID, used as audience.
+ *	@param clientSecret string - Client secret, empty for public clients.
+ *
+ *	@return *Server
+ */
+func NewServer(clientId string, clientSecret string) *Server {
+	server := &Server{
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Code:         "code",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", server.discovery)
+	mux.HandleFunc("/token", server.token)
+
+	server.Server = httptest.NewServer(mux)
+
+	return server
+}
+
+/**
+ *	Returns provider configured against server.
+ *
+ *	@param redirectUrl string - Callback URL.
+ *
+ *	@return *oidc.Provider
+ */
+func (server *Server) Provider(redirectUrl string) *oidc.Provider {
+	return oidc.NewProvider("mock", server.URL, server.ClientId, server.ClientSecret, redirectUrl, []string{"openid", "email", "profile"})
+}
+
+/**
+ *	Returns form of the last token request.
+ *
+ *	@return url.Values
+ */
+func (server *Server) LastTokenRequest() url.Values {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	return server.lastForm
+}
+
+func (server *Server) discovery(writer http.ResponseWriter, request *http.Request) {
+	writeJson(writer, http.StatusOK, oidc.Discovery{
+		Issuer:                server.URL,
+		AuthorizationEndpoint: server.URL + "/authorize",
+		TokenEndpoint:         server.URL + "/token",
+	})
+}
+
+func (server *Server) token(writer http.ResponseWriter, request *http.Request) {
+	request.ParseForm()
+
+	server.lock.Lock()
+	server.lastForm = request.PostForm
+	server.lock.Unlock()
+
+	if server.ClientSecret != "" {
+		clientId, clientSecret, hasBasicAuth := request.BasicAuth()
+
+		if !hasBasicAuth || clientId != server.ClientId || clientSecret != server.ClientSecret {
+			writeJson(writer, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+	}
+
+	if request.PostForm.Get("grant_type") != "authorization_code" || request.PostForm.Get("code") != server.Code {
+		writeJson(writer, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := server.Claims
+	claims.Issuer = server.URL
+	claims.Audience = oidc.Audience{server.ClientId}
+
+	if claims.ExpiresAt == 0 {
+		claims.ExpiresAt = time.Now().Add(time.Hour).Unix()
+	}
+
+	payload, _ := json.Marshal(claims)
+
+	writeJson(writer, http.StatusOK, map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     encodeSegment([]byte(`{"alg":"none"}`)) + "." + encodeSegment(payload) + ".",
+	})
+}
+
+func writeJson(writer http.ResponseWriter, status int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(body)
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,27 @@
+package permissions
+
+import (
+	// Local packages
+	"jaha-api/db"
+	"jaha-api/models"
+)
+
+func init() {
+	// @NOTE Users may only act on themselves.
+	SetOwnerResolver(RESOURCE_USER, func(user models.User, params map[string]string) bool {
+		return params["uuid"] != "" && params["uuid"] == user.UUID
+	})
+
+	// @NOTE Statements are owned by the user that submitted them, deleted statements included.
+	SetOwnerResolver(RESOURCE_STATEMENT, func(user models.User, params map[string]string) bool {
+		var statement models.Statement
+
+		if params["uuid"] == "" {
+			return false
+		}
+
+		db.GetConnection().Unscoped().Where("`uuid` = ?", params["uuid"]).First(&statement)
+
+		return statement.ID != 0 && statement.UserId.Valid && int(statement.UserId.Int64) == user.ID
+	})
+}
@@ -0,0 +1,155 @@
+package permissions
+
+import (
+	// Local packages
+	"jaha-api/models"
+)
+
+const RESOURCE_USER = "user"
+const RESOURCE_CATEGORY = "category"
+const RESOURCE_STATEMENT = "statement"
+const RESOURCE_AUDIT = "audit"
+const RESOURCE_ACCOUNT = "account"
+
+const ACTION_LIST = "list"
+const ACTION_SHOW = "show"
+const ACTION_CREATE = "create"
+const ACTION_UPDATE = "update"
+const ACTION_DESTROY = "destroy"
+const ACTION_RESTORE = "restore"
+const ACTION_TRANSLATE = "translate"
+const ACTION_TRANSITION = "transition"
+const ACTION_VOTE = "vote"
+const ACTION_MODERATE = "moderate"
+const ACTION_UNLOCK = "unlock"
+const ACTION_MANAGE_KEYS = "manageKeys"
+const ACTION_ADMINISTER = "administer"
+const ACTION_HISTORY = "history"
+const ACTION_BATCH = "batch"
+
+/**
+ *	Permission rule for a resource action.
+ *	Role is the lowest role allowed to perform action on any resource, Owner allows resource owners regardless of role.
+ */
+type Rule struct {
+	Role  int
+	Owner bool
+}
+
+type Policy map[string]Rule
+
+/**
+ *	Resolves whether user owns the resource identified by route params.
+ */
+type OwnerResolver func(user models.User, params map[string]string) bool
+
+var policies = map[string]Policy{
+	RESOURCE_USER: Policy{
+		ACTION_LIST:        Rule{Role: models.USER_ROLE_ADMIN},
+		ACTION_SHOW:        Rule{Role: models.USER_ROLE_MOD, Owner: true},
+		ACTION_CREATE:      Rule{Role: models.USER_ROLE_ADMIN},
+		ACTION_UPDATE:      Rule{Role: models.USER_ROLE_ADMIN},
+		ACTION_DESTROY:     Rule{Role: models.USER_ROLE_ADMIN, Owner: true},
+		ACTION_RESTORE:     Rule{Role: models.USER_ROLE_ADMIN},
+		ACTION_UNLOCK:      Rule{Role: models.USER_ROLE_ADMIN},
+		ACTION_MANAGE_KEYS: Rule{Role: models.USER_ROLE_ADMIN, Owner: true},
+		ACTION_ADMINISTER:  Rule{Role: models.USER_ROLE_ADMIN},
+	},
+	RESOURCE_CATEGORY: Policy{
+		ACTION_LIST:    Rule{Role: models.USER_ROLE_GUEST},
+		ACTION_SHOW:    Rule{Role: models.USER_ROLE_GUEST},
+		ACTION_CREATE:  Rule{Role: models.USER_ROLE_MOD},
+		ACTION_UPDATE:  Rule{Role: models.USER_ROLE_MOD},
+		ACTION_DESTROY: Rule{Role: models.USER_ROLE_ADMIN},
+		ACTION_RESTORE: Rule{Role: models.USER_ROLE_ADMIN},
+		ACTION_HISTORY: Rule{Role: models.USER_ROLE_MOD},
+	},
+	RESOURCE_STATEMENT: Policy{
+		ACTION_SHOW:       Rule{Role: models.USER_ROLE_GUEST},
+		ACTION_CREATE:     Rule{Role: models.USER_ROLE_GUEST},
+		ACTION_UPDATE:     Rule{Role: models.USER_ROLE_MOD, Owner: true},
+		ACTION_DESTROY:    Rule{Role: models.USER_ROLE_MOD, Owner: true},
+		ACTION_RESTORE:    Rule{Role: models.USER_ROLE_MOD},
+		ACTION_TRANSLATE:  Rule{Role: models.USER_ROLE_MOD, Owner: true},
+		ACTION_TRANSITION: Rule{Role: models.USER_ROLE_MOD, Owner: true},
+		ACTION_VOTE:       Rule{Role: models.USER_ROLE_GUEST},
+		ACTION_MODERATE:   Rule{Role: models.USER_ROLE_MOD},
+		ACTION_HISTORY:    Rule{Role: models.USER_ROLE_MOD, Owner: true},
+		// @NOTE Batch items are checked one by one against update and destroy rules, see controllers.canBatchItem.
+		ACTION_BATCH: Rule{Role: models.USER_ROLE_GUEST},
+	},
+	RESOURCE_AUDIT: Policy{
+		ACTION_LIST: Rule{Role: models.USER_ROLE_ADMIN},
+	},
+	// @NOTE Account of the authenticated user, any authenticated user acts on their own account.
+	RESOURCE_ACCOUNT: Policy{
+		ACTION_SHOW:    Rule{Role: models.USER_ROLE_GUEST},
+		ACTION_UPDATE:  Rule{Role: models.USER_ROLE_GUEST},
+		ACTION_DESTROY: Rule{Role: models.USER_ROLE_GUEST},
+	},
+}
+
+var ownerResolvers = map[string]OwnerResolver{}
+
+/**
+ *	Sets owner resolver for a resource, resources without resolver have no owners.
+ *
+ *	@param resource string - Resource name.
+ *	@param resolver OwnerResolver - Owner resolver.
+ *
+ *	@return void
+ */
+func SetOwnerResolver(resource string, resolver OwnerResolver) {
+	ownerResolvers[resource] = resolver
+}
+
+/**
+ *	Returns permission rule for resource action, false if action is not defined.
+ *
+ *	@param resource string - Resource name.
+ *	@param action string - Action name.
+ *
+ *	@return Rule, bool
+ */
+func GetRule(resource string, action string) (Rule, bool) {
+	policy, hasPolicy := policies[resource]
+
+	if !hasPolicy {
+		return Rule{}, false
+	}
+
+	rule, hasRule := policy[action]
+
+	return rule, hasRule
+}
+
+/**
+ *	Returns true if user may perform action on resource identified by route params.
+ *	@NOTE Undefined actions are denied, users without ID (not authenticated) are always denied.
+ *
+ *	@param user models.User - User performing action.
+ *	@param resource string - Resource name.
+ *	@param action string - Action name.
+ *	@param params map[string]string - Route params identifying resource.
+ *
+ *	@return bool
+ */
+func Can(user models.User, resource string, action string, params map[string]string) bool {
+	rule, hasRule := GetRule(resource, action)
+
+	if !hasRule || user.ID == 0 {
+		return false
+	}
+
+	if user.Role >= rule.Role {
+		return true
+	}
+
+	if rule.Owner {
+		if resolveOwner, hasResolver := ownerResolvers[resource]; hasResolver {
+			return resolveOwner(user, params)
+		}
+	}
+
+	return false
+}
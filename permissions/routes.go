@@ -0,0 +1,104 @@
+package permissions
+
+import (
+	// Local packages
+	"jaha-api/utils"
+)
+
+/**
+ *	Maps a route pattern to a resource action.
+ */
+type Route struct {
+	Method   string
+	Pattern  string
+	Resource string
+	Action   string
+}
+
+type Routes []Route
+
+// @NOTE Every route behind middlewares.Permissions must be listed, unlisted routes are denied.
+var routes = Routes{
+	Route{"POST", "/v1/auth/logout", RESOURCE_ACCOUNT, ACTION_UPDATE},
+	Route{"POST", "/v1/auth/verification", RESOURCE_ACCOUNT, ACTION_UPDATE},
+
+	Route{"GET", "/v1/me", RESOURCE_ACCOUNT, ACTION_SHOW},
+	Route{"PATCH", "/v1/me", RESOURCE_ACCOUNT, ACTION_UPDATE},
+	Route{"DELETE", "/v1/me", RESOURCE_ACCOUNT, ACTION_DESTROY},
+	Route{"PUT", "/v1/me/password", RESOURCE_ACCOUNT, ACTION_UPDATE},
+	Route{"GET", "/v1/me/export", RESOURCE_ACCOUNT, ACTION_SHOW},
+	Route{"GET", "/v1/favourites", RESOURCE_ACCOUNT, ACTION_SHOW},
+
+	Route{"GET", "/v1/users", RESOURCE_USER, ACTION_LIST},
+	Route{"POST", "/v1/users", RESOURCE_USER, ACTION_CREATE},
+	Route{"GET", "/v1/users/:uuid", RESOURCE_USER, ACTION_SHOW},
+	Route{"PATCH", "/v1/users/:uuid", RESOURCE_USER, ACTION_UPDATE},
+	Route{"DELETE", "/v1/users/:uuid", RESOURCE_USER, ACTION_DESTROY},
+	Route{"PUT", "/v1/users/:uuid", RESOURCE_USER, ACTION_RESTORE},
+	Route{"PUT", "/v1/users/:uuid/auth-key", RESOURCE_USER, ACTION_UPDATE},
+	Route{"GET", "/v1/users/:uuid/lockout", RESOURCE_USER, ACTION_UNLOCK},
+	Route{"DELETE", "/v1/users/:uuid/lockout", RESOURCE_USER, ACTION_UNLOCK},
+	Route{"GET", "/v1/users/:uuid/keys", RESOURCE_USER, ACTION_MANAGE_KEYS},
+	Route{"POST", "/v1/users/:uuid/keys", RESOURCE_USER, ACTION_MANAGE_KEYS},
+	Route{"DELETE", "/v1/users/:uuid/keys/:key", RESOURCE_USER, ACTION_MANAGE_KEYS},
+	Route{"PUT", "/v1/users/:uuid/role", RESOURCE_USER, ACTION_ADMINISTER},
+	Route{"PUT", "/v1/users/:uuid/suspension", RESOURCE_USER, ACTION_ADMINISTER},
+	Route{"DELETE", "/v1/users/:uuid/suspension", RESOURCE_USER, ACTION_ADMINISTER},
+	Route{"DELETE", "/v1/users/:uuid/purge", RESOURCE_USER, ACTION_ADMINISTER},
+	Route{"GET", "/v1/users/:uuid/actions", RESOURCE_USER, ACTION_ADMINISTER},
+
+	Route{"GET", "/v1/categories", RESOURCE_CATEGORY, ACTION_LIST},
+	Route{"POST", "/v1/categories", RESOURCE_CATEGORY, ACTION_CREATE},
+	Route{"GET", "/v1/categories/:uuid", RESOURCE_CATEGORY, ACTION_SHOW},
+	Route{"PATCH", "/v1/categories/:uuid", RESOURCE_CATEGORY, ACTION_UPDATE},
+	Route{"DELETE", "/v1/categories/:uuid", RESOURCE_CATEGORY, ACTION_DESTROY},
+	Route{"PUT", "/v1/categories/:uuid", RESOURCE_CATEGORY, ACTION_RESTORE},
+	Route{"GET", "/v1/categories/:uuid/revisions", RESOURCE_CATEGORY, ACTION_HISTORY},
+	Route{"GET", "/v1/categories/:uuid/revisions/diff", RESOURCE_CATEGORY, ACTION_HISTORY},
+	Route{"POST", "/v1/categories/:uuid/revisions/rollback", RESOURCE_CATEGORY, ACTION_UPDATE},
+
+	Route{"POST", "/v1/statements", RESOURCE_STATEMENT, ACTION_CREATE},
+	Route{"GET", "/v1/statements/:uuid", RESOURCE_STATEMENT, ACTION_SHOW},
+	Route{"PATCH", "/v1/statements/:uuid", RESOURCE_STATEMENT, ACTION_UPDATE},
+	Route{"DELETE", "/v1/statements/:uuid", RESOURCE_STATEMENT, ACTION_DESTROY},
+	Route{"PUT", "/v1/statements/:uuid", RESOURCE_STATEMENT, ACTION_RESTORE},
+	Route{"GET", "/v1/statements/:uuid/translations", RESOURCE_STATEMENT, ACTION_SHOW},
+	Route{"POST", "/v1/statements/:uuid/translations", RESOURCE_STATEMENT, ACTION_TRANSLATE},
+	Route{"PATCH", "/v1/statements/:uuid/translations/:language", RESOURCE_STATEMENT, ACTION_TRANSLATE},
+	Route{"GET", "/v1/statements/:uuid/transitions", RESOURCE_STATEMENT, ACTION_SHOW},
+	Route{"POST", "/v1/statements/:uuid/transitions", RESOURCE_STATEMENT, ACTION_TRANSITION},
+	Route{"GET", "/v1/statements/:uuid/revisions", RESOURCE_STATEMENT, ACTION_HISTORY},
+	Route{"GET", "/v1/statements/:uuid/revisions/diff", RESOURCE_STATEMENT, ACTION_HISTORY},
+	Route{"POST", "/v1/statements/:uuid/revisions/rollback", RESOURCE_STATEMENT, ACTION_UPDATE},
+	Route{"PUT", "/v1/statements/:uuid/vote", RESOURCE_STATEMENT, ACTION_VOTE},
+	Route{"DELETE", "/v1/statements/:uuid/vote", RESOURCE_STATEMENT, ACTION_VOTE},
+	Route{"PUT", "/v1/statements/:uuid/favourite", RESOURCE_STATEMENT, ACTION_VOTE},
+	Route{"DELETE", "/v1/statements/:uuid/favourite", RESOURCE_STATEMENT, ACTION_VOTE},
+	Route{"GET", "/v1/moderation/statements", RESOURCE_STATEMENT, ACTION_MODERATE},
+	Route{"POST", "/v1/batch/statements", RESOURCE_STATEMENT, ACTION_CREATE},
+	Route{"PATCH", "/v1/batch/statements", RESOURCE_STATEMENT, ACTION_BATCH},
+	Route{"DELETE", "/v1/batch/statements", RESOURCE_STATEMENT, ACTION_BATCH},
+
+	Route{"GET", "/v1/audit", RESOURCE_AUDIT, ACTION_LIST},
+
+	Route{"GET", "/v1/lockouts", RESOURCE_USER, ACTION_UNLOCK},
+	Route{"DELETE", "/v1/lockouts/:type/:subject", RESOURCE_USER, ACTION_UNLOCK},
+}
+
+/**
+ *	Finds route matching request method and template of the route matched by router.
+ *
+ *	@param requestMethod string - Request method.
+ *	@param routeTemplate string - Route template, e.g. "/v1/users/:uuid".
+ *
+ *	@return Route, bool
+ */
+func MatchRoute(requestMethod string, routeTemplate string) (Route, bool) {
+	for _, route := range routes {
+		if route.Method == requestMethod && utils.MatchRoute(route.Pattern, routeTemplate) {
+			return route, true
+		}
+	}
+
+	return Route{}, false
+}
@@ -0,0 +1,260 @@
+package purge
+
+import (
+	// Native packages
+	"errors"
+	"log"
+	"time"
+
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+
+	// Local packages
+	"jaha-api/audit"
+	"jaha-api/models"
+)
+
+var ErrNotDeleted = errors.New("Resource is not soft deleted.")
+
+/**
+ *	Permanently removes a soft deleted statement and data that cannot exist without it.
+ *	@NOTE Rooms showing the statement are kept without it.
+ *
+ *	@param tx *gorm.DB - Transaction of the purge.
+ *	@param statementId int - Statement ID.
+ *
+ *	@return error
+ */
+func Statement(tx *gorm.DB, statementId int) error {
+	return execute(tx, statementId, "DELETE FROM `statement` WHERE `id` = ? AND `deleted_at` IS NOT NULL", []string{
+		"DELETE FROM `statement_translation` WHERE `statement_id` = ?",
+		"DELETE FROM `statement_moderation` WHERE `statement_id` = ?",
+		"DELETE FROM `statement_vote` WHERE `statement_id` = ?",
+		"DELETE FROM `statement_favourite` WHERE `statement_id` = ?",
+		"DELETE FROM `game_statement` WHERE `statement_id` = ?",
+		"DELETE FROM `room_answer` WHERE `statement_id` = ?",
+		"UPDATE `room` SET `statement_id` = NULL WHERE `statement_id` = ?",
+		"DELETE FROM `revision` WHERE `resource_type` = '" + audit.RESOURCE_STATEMENT + "' AND `resource_id` = ?",
+	})
+}
+
+/**
+ *	Permanently removes a soft deleted category that no statement belongs to.
+ *	@NOTE Callers must check for statements first, fk_statement_category fails the purge otherwise.
+ *
+ *	@param tx *gorm.DB - Transaction of the purge.
+ *	@param categoryId int - Category ID.
+ *
+ *	@return error
+ */
+func Category(tx *gorm.DB, categoryId int) error {
+	return execute(tx, categoryId, "DELETE FROM `category` WHERE `id` = ? AND `deleted_at` IS NOT NULL", []string{
+		"DELETE FROM `game_category` WHERE `category_id` = ?",
+		"DELETE FROM `revision` WHERE `resource_type` = '" + audit.RESOURCE_CATEGORY + "' AND `resource_id` = ?",
+	})
+}
+
+/**
+ *	Permanently removes a soft deleted user and data that cannot exist without it.
+ *	@NOTE Statements, moderation history and room players are kept without user, votes and favourites are removed from statement counters.
+ *
+ *	@param tx *gorm.DB - Transaction of the purge.
+ *	@param userId int - User ID.
+ *
+ *	@return error
+ */
+func User(tx *gorm.DB, userId int) error {
+	return execute(tx, userId, "DELETE FROM `user` WHERE `id` = ? AND `deleted_at` IS NOT NULL", []string{
+		"UPDATE `statement` INNER JOIN `statement_vote` ON `statement_vote`.`statement_id` = `statement`.`id` " +
+			"SET `statement`.`upvote_count` = `statement`.`upvote_count` - IF(`statement_vote`.`value` > 0, 1, 0), " +
+			"`statement`.`downvote_count` = `statement`.`downvote_count` - IF(`statement_vote`.`value` < 0, 1, 0) " +
+			"WHERE `statement_vote`.`user_id` = ?",
+		"UPDATE `statement` INNER JOIN `statement_favourite` ON `statement_favourite`.`statement_id` = `statement`.`id` " +
+			"SET `statement`.`favourite_count` = `statement`.`favourite_count` - 1 " +
+			"WHERE `statement_favourite`.`user_id` = ?",
+		"DELETE FROM `statement_vote` WHERE `user_id` = ?",
+		"DELETE FROM `statement_favourite` WHERE `user_id` = ?",
+		"DELETE FROM `refresh_token` WHERE `user_id` = ?",
+		"DELETE FROM `revoked_token` WHERE `user_id` = ?",
+		"DELETE FROM `user_token` WHERE `user_id` = ?",
+		"DELETE FROM `api_key` WHERE `user_id` = ?",
+		"DELETE FROM `user_identity` WHERE `user_id` = ?",
+		"UPDATE `statement` SET `user_id` = NULL WHERE `user_id` = ?",
+		"UPDATE `statement_moderation` SET `user_id` = NULL WHERE `user_id` = ?",
+		"UPDATE `revision` SET `user_id` = NULL WHERE `user_id` = ?",
+		"UPDATE `room_player` SET `user_id` = NULL WHERE `user_id` = ?",
+	})
+}
+
+/**
+ *	Permanently removes statements, categories and users soft deleted longer than retention period.
+ *	@NOTE Statements go first so that their categories can follow, categories that still have statements are kept.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param retention time.Duration - Retention period, see env.GetRetentionPeriod.
+ *
+ *	@return models.PurgeReport, error
+ */
+func Run(dbc *gorm.DB, retention time.Duration) (models.PurgeReport, error) {
+	var statements models.Statements
+	var categories models.Categories
+	var users models.Users
+
+	report := models.PurgeReport{Statements: []string{}, Categories: []string{}, Users: []string{}, KeptCategories: []string{}}
+	deletedBefore := time.Now().Add(-retention)
+
+	if queryError := dbc.Unscoped().Where("`deleted_at` < ?", deletedBefore).Find(&statements).Error; queryError != nil {
+		return report, queryError
+	}
+
+	for _, statement := range statements {
+		purged, purgeError := purgeResource(dbc, audit.RESOURCE_STATEMENT, statement.UUID, func(tx *gorm.DB) error {
+			return Statement(tx, statement.ID)
+		})
+
+		if purgeError != nil {
+			return report, purgeError
+		}
+
+		if purged {
+			report.Statements = append(report.Statements, statement.UUID)
+		}
+	}
+
+	if queryError := dbc.Unscoped().Where("`deleted_at` < ?", deletedBefore).Find(&categories).Error; queryError != nil {
+		return report, queryError
+	}
+
+	for _, category := range categories {
+		var statementCount int
+
+		dbc.Model(&models.Statement{}).Unscoped().Where("`category_id` = ?", category.ID).Count(&statementCount)
+
+		if statementCount > 0 {
+			report.KeptCategories = append(report.KeptCategories, category.UUID)
+			continue
+		}
+
+		purged, purgeError := purgeResource(dbc, audit.RESOURCE_CATEGORY, category.UUID, func(tx *gorm.DB) error {
+			return Category(tx, category.ID)
+		})
+
+		if purgeError != nil {
+			return report, purgeError
+		}
+
+		if purged {
+			report.Categories = append(report.Categories, category.UUID)
+		}
+	}
+
+	if queryError := dbc.Unscoped().Where("`deleted_at` < ?", deletedBefore).Find(&users).Error; queryError != nil {
+		return report, queryError
+	}
+
+	for _, user := range users {
+		purged, purgeError := purgeResource(dbc, audit.RESOURCE_USER, user.UUID, func(tx *gorm.DB) error {
+			return User(tx, user.ID)
+		})
+
+		if purgeError != nil {
+			return report, purgeError
+		}
+
+		if purged {
+			report.Users = append(report.Users, user.UUID)
+		}
+	}
+
+	return report, nil
+}
+
+/**
+ *	Runs purge in background every interval, reports are logged.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param retention time.Duration - Retention period, see env.GetRetentionPeriod.
+ *	@param interval time.Duration - Time between runs, see env.GetPurgeInterval.
+ *
+ *	@return void
+ */
+func Start(dbc *gorm.DB, retention time.Duration, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			report, purgeError := Run(dbc, retention)
+
+			if purgeError != nil {
+				log.Printf("Purge failed: %s", purgeError)
+			}
+
+			if report.Count() > 0 || len(report.KeptCategories) > 0 {
+				log.Printf("Purged %s", report)
+			}
+		}
+	}()
+}
+
+/**
+ *	Purges a single resource in its own transaction and records it in the audit log.
+ *	@NOTE Resources restored since they were found are skipped. Audit entry holds only the UUID, audit log outlives purged personal data.
+ *
+ *	@param dbc *gorm.DB - Database connection.
+ *	@param resourceType string - One of audit.RESOURCE_* constants.
+ *	@param resourceUuid string - Resource UUID.
+ *	@param purgeFunc func(tx *gorm.DB) error - Removes resource, see Statement, Category and User.
+ *
+ *	@return bool, error
+ */
+func purgeResource(dbc *gorm.DB, resourceType string, resourceUuid string, purgeFunc func(tx *gorm.DB) error) (bool, error) {
+	tx := dbc.Begin()
+	purgeError := purgeFunc(tx)
+
+	if purgeError == nil {
+		purgeError = audit.Record(tx, models.User{}, audit.ACTION_PURGE, resourceType, resourceUuid, nil, nil)
+	}
+
+	if purgeError != nil {
+		tx.Rollback()
+
+		if purgeError == ErrNotDeleted {
+			return false, nil
+		}
+
+		return false, purgeError
+	}
+
+	return true, tx.Commit().Error
+}
+
+/**
+ *	Executes dependent statements and then removes the resource itself.
+ *
+ *	@param tx *gorm.DB - Transaction of the purge.
+ *	@param resourceId int - Resource ID, bound to every statement.
+ *	@param removeStatement string - Removes the resource, must only match soft deleted rows.
+ *	@param dependentStatements []string - Statements removing or detaching dependent rows.
+ *
+ *	@return error
+ */
+func execute(tx *gorm.DB, resourceId int, removeStatement string, dependentStatements []string) error {
+	for _, dependentStatement := range dependentStatements {
+		if execError := tx.Exec(dependentStatement, resourceId).Error; execError != nil {
+			return execError
+		}
+	}
+
+	removal := tx.Exec(removeStatement, resourceId)
+
+	if removal.Error != nil {
+		return removal.Error
+	}
+
+	if removal.RowsAffected == 0 {
+		return ErrNotDeleted
+	}
+
+	return nil
+}
@@ -35,6 +35,14 @@ func (prototype responseJsonPrototype) Conflict(ctx *gin.Context, responseObject
 	ResponseObject(ctx, 409, responseObject)
 }
 
+func (prototype responseJsonPrototype) Locked(ctx *gin.Context, responseObject interface{}) {
+	ResponseObject(ctx, 423, responseObject)
+}
+
+func (prototype responseJsonPrototype) TooManyRequests(ctx *gin.Context, responseObject interface{}) {
+	ResponseObject(ctx, 429, responseObject)
+}
+
 func (prototype responseJsonPrototype) ServerError(ctx *gin.Context, responseObject interface{}) {
 	ResponseObject(ctx, 500, responseObject)
 }
@@ -35,6 +35,14 @@ func (prototype responseTextPrototype) Conflict(ctx *gin.Context, responseText s
 	ResponseText(ctx, 409, responseText)
 }
 
+func (prototype responseTextPrototype) Locked(ctx *gin.Context, responseText string) {
+	ResponseText(ctx, 423, responseText)
+}
+
+func (prototype responseTextPrototype) TooManyRequests(ctx *gin.Context, responseText string) {
+	ResponseText(ctx, 429, responseText)
+}
+
 func (prototype responseTextPrototype) ServerError(ctx *gin.Context, responseText string) {
 	ResponseText(ctx, 500, responseText)
 }
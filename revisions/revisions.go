@@ -0,0 +1,138 @@
+package revisions
+
+import (
+	// Native packages
+	"encoding/json"
+	"reflect"
+
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+	"gopkg.in/guregu/null.v3"
+
+	// Local packages
+	"jaha-api/audit"
+	"jaha-api/models"
+)
+
+/**
+ *	Appends a revision with resource snapshot after a change, pass the transaction of the change.
+ *	@NOTE Resources created before revisions existed get their state before the change as first revision.
+ *	@NOTE Changes that leave the snapshot unchanged are not kept, rollbacks always are.
+ *
+ *	@param tx *gorm.DB - Transaction of the change.
+ *	@param actor models.User - Acting user, ID is 0 for unauthenticated requests.
+ *	@param resourceType string - One of audit.RESOURCE_* constants, also the resource table.
+ *	@param resourceId int - Resource ID.
+ *	@param before interface{} - Snapshot before change, nil if created.
+ *	@param after interface{} - Snapshot after change.
+ *	@param rollbackOf int - Revision number rolled back to, 0 if not a rollback.
+ *
+ *	@return models.Revision, error
+ */
+func Record(tx *gorm.DB, actor models.User, resourceType string, resourceId int, before interface{}, after interface{}, rollbackOf int) (models.Revision, error) {
+	var latest models.Revision
+
+	// @NOTE Resource row is locked first, reading the latest revision locks nothing while resource has no revisions yet.
+	if lockError := tx.Exec("SELECT `id` FROM `"+resourceType+"` WHERE `id` = ? FOR UPDATE", resourceId).Error; lockError != nil {
+		return latest, lockError
+	}
+
+	tx.Set("gorm:query_option", "FOR UPDATE").Where("`resource_type` = ? AND `resource_id` = ?", resourceType, resourceId).Order("`number` DESC").First(&latest)
+
+	if latest.ID == 0 && before != nil {
+		initial, initialError := newRevision(resourceType, resourceId, 1, before)
+
+		if initialError != nil {
+			return initial, initialError
+		}
+
+		if createError := tx.Create(&initial).Error; createError != nil {
+			return initial, createError
+		}
+
+		latest = initial
+	}
+
+	revision, revisionError := newRevision(resourceType, resourceId, latest.Number+1, after)
+
+	if revisionError != nil {
+		return revision, revisionError
+	}
+
+	if latest.ID != 0 && rollbackOf == 0 {
+		latest.Decode()
+
+		if reflect.DeepEqual(latest.Data, revision.Data) {
+			return latest, nil
+		}
+	}
+
+	if actor.ID != 0 {
+		revision.UserId = null.IntFrom(int64(actor.ID))
+	}
+
+	if rollbackOf != 0 {
+		revision.RollbackOf = null.IntFrom(int64(rollbackOf))
+	}
+
+	return revision, tx.Create(&revision).Error
+}
+
+/**
+ *	Finds revision of a resource by number, ID is 0 if not found.
+ *
+ *	@param dbc *gorm.DB - Database connection or transaction.
+ *	@param resourceType string - One of audit.RESOURCE_* constants.
+ *	@param resourceId int - Resource ID.
+ *	@param number int - Revision number.
+ *
+ *	@return models.Revision
+ */
+func Find(dbc *gorm.DB, resourceType string, resourceId int, number int) models.Revision {
+	var revision models.Revision
+
+	dbc.Where("`resource_type` = ? AND `resource_id` = ? AND `number` = ?", resourceType, resourceId, number).First(&revision)
+	revision.Decode()
+
+	return revision
+}
+
+/**
+ *	Returns changed properties between two revisions of the same resource.
+ *
+ *	@param from models.Revision - Older revision.
+ *	@param to models.Revision - Newer revision.
+ *
+ *	@return models.AuditChanges, error
+ */
+func Diff(from models.Revision, to models.Revision) (models.AuditChanges, error) {
+	return audit.Diff(from.Data, to.Data)
+}
+
+/**
+ *	Returns unsaved revision holding encoded snapshot.
+ *
+ *	@param resourceType string - One of audit.RESOURCE_* constants.
+ *	@param resourceId int - Resource ID.
+ *	@param number int - Revision number.
+ *	@param snapshot interface{} - Resource snapshot.
+ *
+ *	@return models.Revision, error
+ */
+func newRevision(resourceType string, resourceId int, number int, snapshot interface{}) (models.Revision, error) {
+	revision := models.Revision{
+		ResourceType: resourceType,
+		ResourceId:   resourceId,
+		Number:       number,
+	}
+
+	encodedSnapshot, encodeError := json.Marshal(snapshot)
+
+	if encodeError != nil {
+		return revision, encodeError
+	}
+
+	revision.Snapshot = string(encodedSnapshot)
+
+	return revision, revision.Decode()
+}
@@ -26,6 +26,8 @@ func GetDefaultRouter(sessionManager gin.HandlerFunc) *gin.Engine {
 
 	attachDefaultRoutes(router)
 
+	middlewares.SetRoutes(router.Routes())
+
 	return router
 }
 
@@ -44,20 +46,74 @@ func attachDefaultRoutes(router *gin.Engine) {
 
 		Auth := middlewares.AuthMiddleware()
 
-		v1.POST("auth", Auth.LoginHandler)
+		v1.POST("auth", controllers.AuthController().Login)
+		v1.POST("auth/token", controllers.AuthController().Token)
+		v1.POST("auth/forgot", controllers.AuthController().Forgot)
+		v1.POST("auth/reset", controllers.AuthController().Reset)
+		v1.POST("auth/verify", controllers.AuthController().Verify)
+		v1.GET("auth/oidc", controllers.OidcController().Login)
+		v1.GET("auth/oidc/callback", controllers.OidcController().Callback)
+
+		// @NOTE Public endpoints, credentials are optional and identify API clients for preferences and deleted statements, invalid credentials are ignored
+		public := v1.Group("")
+
+		if env.IsProductionMode() {
+			public.Use(middlewares.OptionalAuth(Auth.MiddlewareFunc()))
+		}
 
 		// @NOTE Expose Statement resource endpoint
-		v1.GET("statements", controllers.StatementsController().Index)
+		public.GET("statements", controllers.StatementsController().Index)
+
+		// @NOTE Expose client preferences, stored per API key, user or session
+		public.GET("preferences", controllers.PreferencesController().Show)
+		public.PUT("preferences", controllers.PreferencesController().Update)
+
+		// @NOTE Expose Game resource endpoints, games are played without authentication
+		game := public.Group("games")
+		{
+			game.POST("", controllers.GamesController().Create)
+
+			game.GET(":uuid", controllers.GamesController().Show)
+			game.GET(":uuid/next", controllers.GamesController().Next)
+		}
 
+		// @NOTE Expose Room resource endpoints, guests join rooms without a user account, signed in players are linked to their user
+		room := public.Group("rooms")
+		{
+			room.POST("", controllers.RoomsController().Create)
+
+			room.GET(":code", controllers.RoomsController().Show)
+			room.POST(":code/players", controllers.RoomsController().Join)
+			room.POST(":code/next", controllers.RoomsController().Next)
+			room.POST(":code/answers", controllers.RoomsController().Answer)
+			room.GET(":code/events", controllers.RoomsController().Events)
+		}
+
+		// @NOTE WebSocket clients always authenticate, token is accepted from header or query
+		v1.GET("rooms/:code/socket", middlewares.SocketAuth(), controllers.RoomsController().Socket)
+
+		// @NOTE Permissions require an authenticated user, development mode runs without both
 		if env.IsProductionMode() {
-			v1.Use(Auth.MiddlewareFunc())
+			v1.Use(middlewares.ApiKeyAuth(Auth.MiddlewareFunc()))
+			v1.Use(middlewares.Permissions())
 		}
 
 		v1.Use(middlewares.Constraints())
 
 		auth := v1.Group("auth")
 		{
-			auth.GET("refresh", Auth.RefreshHandler)
+			auth.POST("logout", controllers.AuthController().Logout)
+			auth.POST("verification", controllers.AuthController().SendVerification)
+		}
+
+		me := v1.Group("me")
+		{
+			me.GET("", controllers.MeController().Show)
+			me.PATCH("", controllers.MeController().Update)
+			me.DELETE("", controllers.MeController().Destroy)
+
+			me.PUT("password", controllers.MeController().Password)
+			me.GET("export", controllers.MeController().Export)
 		}
 
 		user := v1.Group("users")
@@ -69,6 +125,21 @@ func attachDefaultRoutes(router *gin.Engine) {
 			user.PATCH(":uuid", controllers.UsersController().Update)
 			user.DELETE(":uuid", controllers.UsersController().Destroy)
 			user.PUT(":uuid", controllers.UsersController().Restore)
+
+			user.PUT(":uuid/auth-key", controllers.UsersController().RotateAuthKey)
+
+			user.GET(":uuid/lockout", controllers.UsersController().Lockout)
+			user.DELETE(":uuid/lockout", controllers.UsersController().Unlock)
+
+			user.GET(":uuid/keys", controllers.ApiKeysController().Index)
+			user.POST(":uuid/keys", controllers.ApiKeysController().Create)
+			user.DELETE(":uuid/keys/:key", controllers.ApiKeysController().Destroy)
+
+			user.PUT(":uuid/role", controllers.UsersController().Role)
+			user.PUT(":uuid/suspension", controllers.UsersController().Suspend)
+			user.DELETE(":uuid/suspension", controllers.UsersController().Unsuspend)
+			user.DELETE(":uuid/purge", controllers.UsersController().Purge)
+			user.GET(":uuid/actions", controllers.UsersController().Actions)
 		}
 
 		category := v1.Group("categories")
@@ -80,6 +151,10 @@ func attachDefaultRoutes(router *gin.Engine) {
 			category.PATCH(":uuid", controllers.CategoriesController().Update)
 			category.DELETE(":uuid", controllers.CategoriesController().Destroy)
 			category.PUT(":uuid", controllers.CategoriesController().Restore)
+
+			category.GET(":uuid/revisions", controllers.CategoryRevisionsController().Index)
+			category.GET(":uuid/revisions/diff", controllers.CategoryRevisionsController().Diff)
+			category.POST(":uuid/revisions/rollback", controllers.CategoryRevisionsController().Rollback)
 		}
 
 		statement := v1.Group("statements")
@@ -91,6 +166,46 @@ func attachDefaultRoutes(router *gin.Engine) {
 			statement.PATCH(":uuid", controllers.StatementsController().Update)
 			statement.DELETE(":uuid", controllers.StatementsController().Destroy)
 			statement.PUT(":uuid", controllers.StatementsController().Restore)
+
+			statement.GET(":uuid/translations", controllers.StatementTranslationsController().Index)
+			statement.POST(":uuid/translations", controllers.StatementTranslationsController().Create)
+			statement.PATCH(":uuid/translations/:language", controllers.StatementTranslationsController().Update)
+
+			statement.GET(":uuid/transitions", controllers.StatementsController().Transitions)
+			statement.POST(":uuid/transitions", controllers.StatementsController().Transition)
+
+			statement.GET(":uuid/revisions", controllers.StatementRevisionsController().Index)
+			statement.GET(":uuid/revisions/diff", controllers.StatementRevisionsController().Diff)
+			statement.POST(":uuid/revisions/rollback", controllers.StatementRevisionsController().Rollback)
+
+			statement.PUT(":uuid/vote", controllers.VotesController().Vote)
+			statement.DELETE(":uuid/vote", controllers.VotesController().Unvote)
+			statement.PUT(":uuid/favourite", controllers.VotesController().Favourite)
+			statement.DELETE(":uuid/favourite", controllers.VotesController().Unfavourite)
+		}
+
+		// @NOTE Batch endpoints take arrays of items, statement routes cannot hold a static segment next to :uuid
+		batch := v1.Group("batch")
+		{
+			batch.POST("statements", controllers.StatementBatchesController().Create)
+			batch.PATCH("statements", controllers.StatementBatchesController().Update)
+			batch.DELETE("statements", controllers.StatementBatchesController().Destroy)
+		}
+
+		v1.GET("favourites", controllers.VotesController().Favourites)
+
+		v1.GET("audit", controllers.AuditController().Index)
+
+		// @NOTE Failed login attempts of emails and client IP addresses, user lockouts are also under users
+		lockout := v1.Group("lockouts")
+		{
+			lockout.GET("", controllers.LockoutsController().Index)
+			lockout.DELETE(":type/:subject", controllers.LockoutsController().Destroy)
+		}
+
+		moderation := v1.Group("moderation")
+		{
+			moderation.GET("statements", controllers.ModerationController().Index)
 		}
 	}
 
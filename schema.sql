@@ -4,6 +4,7 @@ CREATE TABLE `category` (
 	`uuid` VARCHAR(8) NOT NULL,
 	`name` VARCHAR(255) NOT NULL,
 	`slug` VARCHAR(255) NOT NULL,
+	`default_level` TINYINT(1) unsigned NOT NULL DEFAULT 4,
 	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
 	`deleted_at` DATETIME DEFAULT NULL,
 	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -19,11 +20,20 @@ CREATE TABLE `statement` (
 	`uuid` VARCHAR(8) NOT NULL,
 	`category_id` INT(11) unsigned NOT NULL,
 	`body` TEXT NOT NULL,
+	`status` VARCHAR(16) NOT NULL DEFAULT 'published',
+	`level` TINYINT(1) unsigned DEFAULT NULL,
+	`upvote_count` INT(11) unsigned NOT NULL DEFAULT 0,
+	`downvote_count` INT(11) unsigned NOT NULL DEFAULT 0,
+	`favourite_count` INT(11) unsigned NOT NULL DEFAULT 0,
+	`user_id` INT(11) unsigned DEFAULT NULL,
 	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
 	`deleted_at` DATETIME DEFAULT NULL,
+	`deleted_with_category` TINYINT(1) NOT NULL DEFAULT 0,
 	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	PRIMARY KEY (`id`),
 	UNIQUE KEY `uuid` (`uuid`),
+	KEY `status` (`status`),
+	FULLTEXT KEY `body` (`body`),
 	CONSTRAINT `fk_statement_category`
 		FOREIGN KEY (`category_id`) REFERENCES `category` (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -38,6 +48,11 @@ CREATE TABLE `user` (
 	`password` TEXT NOT NULL,
 	`auth_key` VARCHAR(16) NOT NULL,
 	`role` INT(11) unsigned NOT NULL DEFAULT 1,
+	`safe_mode` TINYINT(1) NOT NULL DEFAULT 0,
+	`max_level` TINYINT(1) unsigned NOT NULL DEFAULT 0,
+	`verified_at` DATETIME DEFAULT NULL,
+	`suspended_at` DATETIME DEFAULT NULL,
+	`suspension_reason` TEXT DEFAULT NULL,
 	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
 	`deleted_at` DATETIME DEFAULT NULL,
 	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -45,3 +60,290 @@ CREATE TABLE `user` (
 	UNIQUE KEY `uuid` (`uuid`),
 	UNIQUE KEY `email` (`email`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `game`;
+CREATE TABLE `game` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`uuid` VARCHAR(8) NOT NULL,
+	`max_level` TINYINT(1) unsigned DEFAULT NULL,
+	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+	`deleted_at` DATETIME DEFAULT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `uuid` (`uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `game_category`;
+CREATE TABLE `game_category` (
+	`game_id` INT(11) unsigned NOT NULL,
+	`category_id` INT(11) unsigned NOT NULL,
+	PRIMARY KEY (`game_id`, `category_id`),
+	CONSTRAINT `fk_game_category_game`
+		FOREIGN KEY (`game_id`) REFERENCES `game` (`id`),
+	CONSTRAINT `fk_game_category_category`
+		FOREIGN KEY (`category_id`) REFERENCES `category` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `game_statement`;
+CREATE TABLE `game_statement` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`game_id` INT(11) unsigned NOT NULL,
+	`statement_id` INT(11) unsigned NOT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `game_statement` (`game_id`, `statement_id`),
+	CONSTRAINT `fk_game_statement_game`
+		FOREIGN KEY (`game_id`) REFERENCES `game` (`id`),
+	CONSTRAINT `fk_game_statement_statement`
+		FOREIGN KEY (`statement_id`) REFERENCES `statement` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `room`;
+CREATE TABLE `room` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`uuid` VARCHAR(8) NOT NULL,
+	`code` VARCHAR(6) NOT NULL,
+	`game_id` INT(11) unsigned NOT NULL,
+	`statement_id` INT(11) unsigned DEFAULT NULL,
+	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+	`deleted_at` DATETIME DEFAULT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `uuid` (`uuid`),
+	UNIQUE KEY `code` (`code`),
+	CONSTRAINT `fk_room_game`
+		FOREIGN KEY (`game_id`) REFERENCES `game` (`id`),
+	CONSTRAINT `fk_room_statement`
+		FOREIGN KEY (`statement_id`) REFERENCES `statement` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `room_player`;
+CREATE TABLE `room_player` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`uuid` VARCHAR(8) NOT NULL,
+	`room_id` INT(11) unsigned NOT NULL,
+	`user_id` INT(11) unsigned DEFAULT NULL,
+	`name` VARCHAR(32) NOT NULL,
+	`token` VARCHAR(16) NOT NULL,
+	`have_count` INT(11) unsigned NOT NULL DEFAULT 0,
+	`have_never_count` INT(11) unsigned NOT NULL DEFAULT 0,
+	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+	`deleted_at` DATETIME DEFAULT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `uuid` (`uuid`),
+	UNIQUE KEY `room_name` (`room_id`, `name`),
+	CONSTRAINT `fk_room_player_room`
+		FOREIGN KEY (`room_id`) REFERENCES `room` (`id`),
+	CONSTRAINT `fk_room_player_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `room_answer`;
+CREATE TABLE `room_answer` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`room_id` INT(11) unsigned NOT NULL,
+	`room_player_id` INT(11) unsigned NOT NULL,
+	`statement_id` INT(11) unsigned NOT NULL,
+	`answer` VARCHAR(16) NOT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `room_player_statement` (`room_player_id`, `statement_id`),
+	CONSTRAINT `fk_room_answer_room`
+		FOREIGN KEY (`room_id`) REFERENCES `room` (`id`),
+	CONSTRAINT `fk_room_answer_room_player`
+		FOREIGN KEY (`room_player_id`) REFERENCES `room_player` (`id`),
+	CONSTRAINT `fk_room_answer_statement`
+		FOREIGN KEY (`statement_id`) REFERENCES `statement` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `statement_translation`;
+CREATE TABLE `statement_translation` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`statement_id` INT(11) unsigned NOT NULL,
+	`language` VARCHAR(8) NOT NULL,
+	`body` TEXT NOT NULL,
+	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+	`deleted_at` DATETIME DEFAULT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `statement_language` (`statement_id`, `language`),
+	CONSTRAINT `fk_statement_translation_statement`
+		FOREIGN KEY (`statement_id`) REFERENCES `statement` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `statement_moderation`;
+CREATE TABLE `statement_moderation` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`statement_id` INT(11) unsigned NOT NULL,
+	`user_id` INT(11) unsigned DEFAULT NULL,
+	`from_status` VARCHAR(16) NOT NULL,
+	`to_status` VARCHAR(16) NOT NULL,
+	`reason` TEXT NOT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	CONSTRAINT `fk_statement_moderation_statement`
+		FOREIGN KEY (`statement_id`) REFERENCES `statement` (`id`),
+	CONSTRAINT `fk_statement_moderation_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `statement_vote`;
+CREATE TABLE `statement_vote` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`statement_id` INT(11) unsigned NOT NULL,
+	`user_id` INT(11) unsigned NOT NULL,
+	`value` TINYINT(1) NOT NULL,
+	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `statement_user` (`statement_id`, `user_id`),
+	CONSTRAINT `fk_statement_vote_statement`
+		FOREIGN KEY (`statement_id`) REFERENCES `statement` (`id`),
+	CONSTRAINT `fk_statement_vote_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `statement_favourite`;
+CREATE TABLE `statement_favourite` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`statement_id` INT(11) unsigned NOT NULL,
+	`user_id` INT(11) unsigned NOT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `statement_user` (`statement_id`, `user_id`),
+	CONSTRAINT `fk_statement_favourite_statement`
+		FOREIGN KEY (`statement_id`) REFERENCES `statement` (`id`),
+	CONSTRAINT `fk_statement_favourite_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `refresh_token`;
+CREATE TABLE `refresh_token` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`user_id` INT(11) unsigned NOT NULL,
+	`token_hash` CHAR(64) NOT NULL,
+	`expires_at` DATETIME NOT NULL,
+	`revoked_at` DATETIME DEFAULT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `token_hash` (`token_hash`),
+	KEY `user_id` (`user_id`),
+	CONSTRAINT `fk_refresh_token_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `revoked_token`;
+CREATE TABLE `revoked_token` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`jti` VARCHAR(32) NOT NULL,
+	`user_id` INT(11) unsigned NOT NULL,
+	`expires_at` DATETIME NOT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `jti` (`jti`),
+	KEY `expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `user_token`;
+CREATE TABLE `user_token` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`user_id` INT(11) unsigned NOT NULL,
+	`purpose` VARCHAR(16) NOT NULL,
+	`token_hash` CHAR(64) NOT NULL,
+	`expires_at` DATETIME NOT NULL,
+	`used_at` DATETIME DEFAULT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `token_hash` (`token_hash`),
+	KEY `user_purpose` (`user_id`, `purpose`),
+	CONSTRAINT `fk_user_token_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `login_lockout`;
+CREATE TABLE `login_lockout` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`subject_type` VARCHAR(8) NOT NULL,
+	`subject` VARCHAR(255) NOT NULL,
+	`failure_count` INT(11) unsigned NOT NULL DEFAULT 0,
+	`last_failure_at` DATETIME DEFAULT NULL,
+	`locked_until` DATETIME DEFAULT NULL,
+	`updated_at` DATETIME DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `subject` (`subject_type`, `subject`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `api_key`;
+CREATE TABLE `api_key` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`uuid` VARCHAR(8) NOT NULL,
+	`user_id` INT(11) unsigned NOT NULL,
+	`name` VARCHAR(255) NOT NULL,
+	`prefix` CHAR(8) NOT NULL,
+	`key_hash` CHAR(64) NOT NULL,
+	`role` INT(11) unsigned NOT NULL DEFAULT 1,
+	`safe_mode` TINYINT(1) NOT NULL DEFAULT 0,
+	`max_level` TINYINT(1) unsigned NOT NULL DEFAULT 0,
+	`last_used_at` DATETIME DEFAULT NULL,
+	`revoked_at` DATETIME DEFAULT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `uuid` (`uuid`),
+	UNIQUE KEY `key_hash` (`key_hash`),
+	CONSTRAINT `fk_api_key_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `user_identity`;
+CREATE TABLE `user_identity` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`user_id` INT(11) unsigned NOT NULL,
+	`provider` VARCHAR(64) NOT NULL,
+	`subject` VARCHAR(255) NOT NULL,
+	`email` VARCHAR(255) NOT NULL DEFAULT '',
+	`last_login_at` DATETIME DEFAULT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `provider_subject` (`provider`, `subject`),
+	CONSTRAINT `fk_user_identity_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `audit_entry`;
+CREATE TABLE `audit_entry` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`actor_id` INT(11) unsigned DEFAULT NULL,
+	`action` VARCHAR(16) NOT NULL,
+	`resource_type` VARCHAR(32) NOT NULL,
+	`resource_uuid` VARCHAR(8) NOT NULL,
+	`changes` TEXT NOT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	KEY `actor_id` (`actor_id`),
+	KEY `resource` (`resource_type`, `resource_uuid`),
+	KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TRIGGER `audit_entry_no_update` BEFORE UPDATE ON `audit_entry`
+	FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_entry is append-only';
+
+CREATE TRIGGER `audit_entry_no_delete` BEFORE DELETE ON `audit_entry`
+	FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_entry is append-only';
+
+DROP TABLE IF EXISTS `revision`;
+CREATE TABLE `revision` (
+	`id` INT(11) unsigned NOT NULL AUTO_INCREMENT,
+	`resource_type` VARCHAR(32) NOT NULL,
+	`resource_id` INT(11) unsigned NOT NULL,
+	`number` INT(11) unsigned NOT NULL,
+	`user_id` INT(11) unsigned DEFAULT NULL,
+	`rollback_of` INT(11) unsigned DEFAULT NULL,
+	`snapshot` TEXT NOT NULL,
+	`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (`id`),
+	UNIQUE KEY `resource_number` (`resource_type`, `resource_id`, `number`),
+	CONSTRAINT `fk_revision_user`
+		FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -8,7 +8,7 @@ import (
 type categoryScopes struct{}
 
 func (categoryScopes) Deleted(dbc *gorm.DB) *gorm.DB {
-	return dbc.Where("`deleted_at` IS NOT NULL")
+	return dbc.Where("`category`.`deleted_at` IS NOT NULL")
 }
 
 func Category() categoryScopes {
@@ -0,0 +1,48 @@
+package scopes
+
+import (
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+
+	// Local packages
+	"jaha-api/models"
+)
+
+type gameScopes struct{}
+
+/**
+ *	Returns scope limiting statements to published statements in the categories and level of a game deck.
+ *
+ *	@param game models.Game
+ *
+ *	@return func(*gorm.DB) *gorm.DB
+ */
+func (gameScopes) Deck(game models.Game) func(*gorm.DB) *gorm.DB {
+	return func(dbc *gorm.DB) *gorm.DB {
+		dbc = dbc.Scopes(Statement().Published).Where("`statement`.`category_id` IN (?)", game.GetCategoryIds())
+
+		if game.MaxLevel.Valid {
+			dbc = dbc.Scopes(Statement().MaxLevel(int(game.MaxLevel.Int64)))
+		}
+
+		return dbc
+	}
+}
+
+/**
+ *	Returns scope limiting statements to those not yet drawn in a game.
+ *
+ *	@param game models.Game
+ *
+ *	@return func(*gorm.DB) *gorm.DB
+ */
+func (gameScopes) Undrawn(game models.Game) func(*gorm.DB) *gorm.DB {
+	return func(dbc *gorm.DB) *gorm.DB {
+		return dbc.Where("`statement`.`id` NOT IN (SELECT `statement_id` FROM `game_statement` WHERE `game_id` = ?)", game.ID)
+	}
+}
+
+func Game() gameScopes {
+	var scopes gameScopes
+	return scopes
+}
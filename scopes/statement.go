@@ -3,18 +3,65 @@ package scopes
 import (
 	// 3rd party packages
 	"github.com/jinzhu/gorm"
+
+	// Local packages
+	"jaha-api/models"
 )
 
 type statementScopes struct{}
 
 func (statementScopes) Deleted(dbc *gorm.DB) *gorm.DB {
-	return dbc.Where("`deleted_at` IS NOT NULL")
+	return dbc.Where("`statement`.`deleted_at` IS NOT NULL")
+}
+
+func (statementScopes) Published(dbc *gorm.DB) *gorm.DB {
+	return dbc.Where("`statement`.`status` = ?", models.STATEMENT_STATUS_PUBLISHED)
+}
+
+func (statementScopes) Pending(dbc *gorm.DB) *gorm.DB {
+	return dbc.Where("`statement`.`status` = ?", models.STATEMENT_STATUS_PENDING)
+}
+
+/**
+ *	Orders statements by time-decayed vote score, newer statements need fewer votes to rank high.
+ *	@NOTE Score is (upvotes - downvotes) / (age in hours + 2) ^ 1.5, same decay as Hacker News ranking.
+ */
+func (statementScopes) Popular(dbc *gorm.DB) *gorm.DB {
+	return dbc.Order("(CAST(`statement`.`upvote_count` AS SIGNED) - CAST(`statement`.`downvote_count` AS SIGNED)) / POW(TIMESTAMPDIFF(HOUR, `statement`.`created_at`, NOW()) + 2, 1.5) DESC")
 }
 
 func (statementScopes) Random(dbc *gorm.DB) *gorm.DB {
 	return dbc.Where("RAND() < (SELECT ((1 / COUNT(*)) * 10) FROM `statement`)").Order("RAND()")
 }
 
+/**
+ *	Returns scope limiting statements to level or lower, statements without level use their category default level.
+ *	@NOTE Fails closed, statements without any level count as models.LEVEL_EXPLICIT.
+ *
+ *	@param maxLevel int
+ *
+ *	@return func(*gorm.DB) *gorm.DB
+ */
+func (statementScopes) MaxLevel(maxLevel int) func(*gorm.DB) *gorm.DB {
+	return func(dbc *gorm.DB) *gorm.DB {
+		return dbc.Where("COALESCE(`statement`.`level`, (SELECT `level_category`.`default_level` FROM `category` AS `level_category` WHERE `level_category`.`id` = `statement`.`category_id`), ?) <= ?", models.LEVEL_EXPLICIT, maxLevel)
+	}
+}
+
+/**
+ *	Returns scope matching statements against full-text search query, adds "relevance" column for ranking.
+ *
+ *	@param searchQuery string
+ *
+ *	@return func(*gorm.DB) *gorm.DB
+ */
+func (statementScopes) Search(searchQuery string) func(*gorm.DB) *gorm.DB {
+	return func(dbc *gorm.DB) *gorm.DB {
+		return dbc.Select("`statement`.*, MATCH(`statement`.`body`) AGAINST (? IN NATURAL LANGUAGE MODE) AS `relevance`", searchQuery).
+			Where("MATCH(`statement`.`body`) AGAINST (? IN NATURAL LANGUAGE MODE)", searchQuery)
+	}
+}
+
 func Statement() statementScopes {
 	var scopes statementScopes
 	return scopes
@@ -0,0 +1,17 @@
+package scopes
+
+import (
+	// 3rd party packages
+	"github.com/jinzhu/gorm"
+)
+
+type userScopes struct{}
+
+func (userScopes) Deleted(dbc *gorm.DB) *gorm.DB {
+	return dbc.Where("`user`.`deleted_at` IS NOT NULL")
+}
+
+func User() userScopes {
+	var scopes userScopes
+	return scopes
+}
@@ -56,11 +56,14 @@ func MapOrderByConditions(orderByConditions string) map[string]string {
 func FilterOrderByConditions(query *gorm.DB, model interface{}, orderByConditions map[string]string) *gorm.DB {
 	modelMap := structs.Map(model)
 
+	// @NOTE Qualify columns with table name, joined tables share column names like "created_at".
+	tableName := query.NewScope(model).TableName()
+
 	for conditionCol, conditionDir := range orderByConditions {
 		columnFieldName := strings.Title(conditionCol)
 		_, hasField := modelMap[columnFieldName]
 		if hasField == true || conditionCol == "id" {
-			query = query.Order(fmt.Sprintf("%s %s", snaker.CamelToSnake(conditionCol), conditionDir))
+			query = query.Order(fmt.Sprintf("`%s`.`%s` %s", tableName, snaker.CamelToSnake(conditionCol), conditionDir))
 		}
 	}
 
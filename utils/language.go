@@ -0,0 +1,112 @@
+package utils
+
+import (
+	// Native packages
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type acceptedLanguage struct {
+	tag     string
+	quality float64
+}
+
+type acceptedLanguages []acceptedLanguage
+
+func (languages acceptedLanguages) Len() int {
+	return len(languages)
+}
+
+func (languages acceptedLanguages) Less(i int, j int) bool {
+	return languages[i].quality > languages[j].quality
+}
+
+func (languages acceptedLanguages) Swap(i int, j int) {
+	languages[i], languages[j] = languages[j], languages[i]
+}
+
+/**
+ *	Parses Accept-Language header into language tags ordered by quality, highest first.
+ *
+ *	@example
+ *		en-GB;q=0.8, sv, *;q=0.1 >> [sv en-gb]
+ *
+ *	@param acceptLanguage string - Accept-Language header value.
+ *
+ *	@return []string
+ */
+func ParseAcceptLanguage(acceptLanguage string) []string {
+	var accepted acceptedLanguages
+	var languageTags []string
+
+	for _, languageRange := range strings.Split(acceptLanguage, ",") {
+		rangeParts := strings.Split(strings.TrimSpace(languageRange), ";")
+		languageTag := strings.ToLower(strings.TrimSpace(rangeParts[0]))
+		languageQuality := 1.0
+
+		for _, rangeParam := range rangeParts[1:] {
+			rangeParam = strings.TrimSpace(rangeParam)
+
+			if strings.HasPrefix(rangeParam, "q=") {
+				parsedQuality, parseError := strconv.ParseFloat(strings.TrimPrefix(rangeParam, "q="), 64)
+
+				if parseError == nil {
+					languageQuality = parsedQuality
+				}
+			}
+		}
+
+		if languageTag == "" || languageTag == "*" || languageQuality <= 0 {
+			continue
+		}
+
+		accepted = append(accepted, acceptedLanguage{
+			tag:     languageTag,
+			quality: languageQuality,
+		})
+	}
+
+	sort.Stable(accepted)
+
+	for _, language := range accepted {
+		languageTags = append(languageTags, language.tag)
+	}
+
+	return languageTags
+}
+
+/**
+ *	Builds language fallback chain, each tag is followed by its primary language and chain ends with default language.
+ *
+ *	@example
+ *		[nb-NO en] sv >> [nb-no nb en sv]
+ *
+ *	@param preferredLanguages []string - Language tags in order of preference, empty tags are skipped.
+ *	@param defaultLanguage string - Language to end chain with.
+ *
+ *	@return []string
+ */
+func LanguageFallbacks(preferredLanguages []string, defaultLanguage string) []string {
+	var fallbacks []string
+
+	seenLanguages := make(map[string]bool)
+
+	addLanguage := func(language string) {
+		language = strings.ToLower(strings.TrimSpace(language))
+
+		if language != "" && !seenLanguages[language] {
+			seenLanguages[language] = true
+			fallbacks = append(fallbacks, language)
+		}
+	}
+
+	for _, language := range preferredLanguages {
+		addLanguage(language)
+		addLanguage(strings.Split(language, "-")[0])
+	}
+
+	addLanguage(defaultLanguage)
+
+	return fallbacks
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	// Native packages
+	"strings"
+)
+
+/**
+ *	Matches a router template against a route pattern, e.g. "/v1/users/:uuid" or "/v1/statements/*".
+ *	Segments are compared as written, a trailing wildcard ("*" or "*name") matches one or more remaining segments but not the parent route.
+ *
+ *	@param routePattern string - Route pattern, same syntax as router templates.
+ *	@param routeTemplate string - Template of the route matched by router.
+ *
+ *	@return bool
+ */
+func MatchRoute(routePattern string, routeTemplate string) bool {
+	patternSegments := strings.Split(strings.Trim(routePattern, "/"), "/")
+	templateSegments := strings.Split(strings.Trim(routeTemplate, "/"), "/")
+
+	for index, patternSegment := range patternSegments {
+		if strings.HasPrefix(patternSegment, "*") {
+			// @NOTE Wildcards are only allowed as the last segment, same as in router.
+			return index == len(patternSegments)-1 && index < len(templateSegments)
+		}
+
+		if index >= len(templateSegments) || patternSegment != templateSegments[index] {
+			return false
+		}
+	}
+
+	return len(patternSegments) == len(templateSegments)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	// Native packages
+	"testing"
+)
+
+func TestMatchRoute(t *testing.T) {
+	cases := []struct {
+		routePattern  string
+		routeTemplate string
+		expected      bool
+	}{
+		{"/v1/users", "/v1/users", true},
+		{"/v1/users/", "/v1/users", true},
+		{"/v1/users", "/v1/categories", false},
+		{"/v1/users/:uuid", "/v1/users/:uuid", true},
+		{"/v1/users/:uuid", "/v1/users/:id", false},
+		{"/v1/users/:uuid", "/v1/users", false},
+		{"/v1/users", "/v1/users/:uuid", false},
+		{"/v1/users/:uuid", "/v1/users/:uuid/keys", false},
+		{"/v1/users/:uuid/keys/:key", "/v1/users/:uuid/keys", false},
+		{"/v1/statements/*", "/v1/statements/:uuid", true},
+		{"/v1/statements/*", "/v1/statements/:uuid/revisions/diff", true},
+		{"/v1/statements/*path", "/v1/statements/:uuid/vote", true},
+		{"/v1/statements/*", "/v1/statements", false},
+		{"/v1/statements/*", "/v1/categories/:uuid", false},
+		{"/v1/*/revisions", "/v1/statements/revisions", false},
+	}
+
+	for _, testCase := range cases {
+		if matched := MatchRoute(testCase.routePattern, testCase.routeTemplate); matched != testCase.expected {
+			t.Errorf("MatchRoute(%q, %q) returned %t, expected %t", testCase.routePattern, testCase.routeTemplate, matched, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	// Native packages
+	"strings"
+	"unicode"
+)
+
+var diacriticFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y",
+	'ß': "ss",
+}
+
+/**
+ *	Normalizes text for comparison, folds case and diacritics, drops punctuation and collapses whitespace.
+ *
+ *	@example
+ *		"Jag har aldrig åkt  skidor!" >> "jag har aldrig akt skidor"
+ *
+ *	@param text string
+ *
+ *	@return string
+ */
+func NormalizeText(text string) string {
+	var normalized []string
+	var word []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			normalized = append(normalized, string(word))
+			word = word[:0]
+		}
+	}
+
+	for _, character := range strings.ToLower(text) {
+		if folded, isDiacritic := diacriticFolds[character]; isDiacritic {
+			word = append(word, []rune(folded)...)
+		} else if unicode.IsLetter(character) || unicode.IsNumber(character) {
+			word = append(word, character)
+		} else {
+			flushWord()
+		}
+	}
+
+	flushWord()
+
+	return strings.Join(normalized, " ")
+}
+
+/**
+ *	Returns similarity between two texts in range 0-1, using token overlap (Jaccard index) of normalized texts.
+ *
+ *	@param text string
+ *	@param otherText string
+ *
+ *	@return float64
+ */
+func Similarity(text string, otherText string) float64 {
+	normalizedText := NormalizeText(text)
+	normalizedOtherText := NormalizeText(otherText)
+
+	if normalizedText == normalizedOtherText {
+		return 1
+	}
+
+	tokens := make(map[string]bool)
+	otherTokens := make(map[string]bool)
+
+	for _, token := range strings.Fields(normalizedText) {
+		tokens[token] = true
+	}
+
+	for _, token := range strings.Fields(normalizedOtherText) {
+		otherTokens[token] = true
+	}
+
+	sharedCount := 0
+
+	for token := range tokens {
+		if otherTokens[token] {
+			sharedCount++
+		}
+	}
+
+	unionCount := len(tokens) + len(otherTokens) - sharedCount
+
+	if unionCount == 0 {
+		return 0
+	}
+
+	return float64(sharedCount) / float64(unionCount)
+}
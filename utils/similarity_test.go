@@ -0,0 +1,59 @@
+package utils_test
+
+import (
+	// Native packages
+	"math"
+	"testing"
+
+	// Local packages
+	"jaha-api/models"
+	"jaha-api/utils"
+)
+
+func TestNormalizeText(t *testing.T) {
+	cases := map[string]string{
+		"Jag har aldrig åkt  skidor!": "jag har aldrig akt skidor",
+		"  Æble, ØL & STRAßE ":        "aeble ol strasse",
+		"...":                         "",
+	}
+
+	for text, expected := range cases {
+		if normalized := utils.NormalizeText(text); normalized != expected {
+			t.Errorf("NormalizeText(%q) returned %q, expected %q", text, normalized, expected)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	cases := []struct {
+		name        string
+		text        string
+		otherText   string
+		similarity  float64
+		isDuplicate bool
+	}{
+		{"identical", "Jag har aldrig åkt skidor", "Jag har aldrig åkt skidor", 1, true},
+		{"case, punctuation and diacritics", "Jag har aldrig åkt skidor!", "jag har ALDRIG akt skidor", 1, true},
+		{"reordered", "Jag har aldrig åkt skidor", "Skidor har jag aldrig åkt", 1, true},
+		{"one extra word of nine", "Jag har aldrig åkt skidor i de svenska fjällen", "Jag har aldrig åkt skidor i svenska fjällen", 8.0 / 9.0, true},
+		{"threshold exactly", "a b c d", "a b c d e", 0.8, true},
+		{"one other word of five", "Jag har aldrig åkt skidor", "Jag har aldrig åkt skridskor", 4.0 / 6.0, false},
+		{"unrelated", "Jag har aldrig åkt skidor", "Never have I ever been to Paris", 0, false},
+	}
+
+	for _, testCase := range cases {
+		similarity := utils.Similarity(testCase.text, testCase.otherText)
+
+		if math.Abs(similarity-testCase.similarity) > 1e-9 {
+			t.Errorf("Similarity of %s returned %f, expected %f", testCase.name, similarity, testCase.similarity)
+		}
+
+		if reversed := utils.Similarity(testCase.otherText, testCase.text); reversed != similarity {
+			t.Errorf("Similarity of %s is not symmetric, %f and %f", testCase.name, similarity, reversed)
+		}
+
+		if isDuplicate := similarity >= models.STATEMENT_DUPLICATE_THRESHOLD; isDuplicate != testCase.isDuplicate {
+			t.Errorf("Similarity of %s is %f, near-duplicate %t, expected %t", testCase.name, similarity, isDuplicate, testCase.isDuplicate)
+		}
+	}
+}
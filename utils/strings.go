@@ -2,8 +2,16 @@ package utils
 
 import (
 	// Native packages
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
 	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
 
 	// 3rd party packages
 	"golang.org/x/crypto/bcrypt"
@@ -26,6 +34,97 @@ func Pick(expectedString string, defaultString string) string {
 	return expectedString
 }
 
+type stringsByLength []string
+
+func (values stringsByLength) Len() int {
+	return len(values)
+}
+
+func (values stringsByLength) Less(i int, j int) bool {
+	return len(values[i]) > len(values[j])
+}
+
+func (values stringsByLength) Swap(i int, j int) {
+	values[i], values[j] = values[j], values[i]
+}
+
+/**
+ *	Splits search query into unique lowercase terms, punctuation and search operators are dropped.
+ *
+ *	@example
+ *		"Jag har +aldrig, jag" >> [jag har aldrig]
+ *
+ *	@param searchQuery string
+ *
+ *	@return []string
+ */
+func SearchTerms(searchQuery string) []string {
+	var searchTerms []string
+
+	seenTerms := make(map[string]bool)
+	queryWords := strings.FieldsFunc(strings.ToLower(searchQuery), func(character rune) bool {
+		return !unicode.IsLetter(character) && !unicode.IsNumber(character)
+	})
+
+	for _, word := range queryWords {
+		if !seenTerms[word] {
+			seenTerms[word] = true
+			searchTerms = append(searchTerms, word)
+		}
+	}
+
+	return searchTerms
+}
+
+/**
+ *	Wraps case insensitive occurrences of search terms in text with before and after markers.
+ *	@NOTE Text is HTML escaped around the markers, markers themselves are written as is.
+ *
+ *	@example
+ *		"Jag har <b>aldrig</b>", [aldrig], "<mark>", "</mark>" >> "Jag har &lt;b&gt;<mark>aldrig</mark>&lt;/b&gt;"
+ *
+ *	@param text string - Text to highlight.
+ *	@param searchTerms []string - Terms to highlight, see SearchTerms.
+ *	@param before string - Marker inserted before each occurrence.
+ *	@param after string - Marker inserted after each occurrence.
+ *
+ *	@return string
+ */
+func Highlight(text string, searchTerms []string, before string, after string) string {
+	var quotedTerms []string
+	var highlighted bytes.Buffer
+
+	for _, term := range searchTerms {
+		if term != "" {
+			quotedTerms = append(quotedTerms, regexp.QuoteMeta(term))
+		}
+	}
+
+	if len(quotedTerms) == 0 {
+		return html.EscapeString(text)
+	}
+
+	// @NOTE Longest terms first, otherwise "al" would win over "aldrig".
+	sort.Stable(stringsByLength(quotedTerms))
+
+	termPattern := regexp.MustCompile("(?i)(" + strings.Join(quotedTerms, "|") + ")")
+
+	// @NOTE Terms are matched in unescaped text, so that they never match inside escaped entities.
+	lastIndex := 0
+
+	for _, match := range termPattern.FindAllStringIndex(text, -1) {
+		highlighted.WriteString(html.EscapeString(text[lastIndex:match[0]]))
+		highlighted.WriteString(before)
+		highlighted.WriteString(html.EscapeString(text[match[0]:match[1]]))
+		highlighted.WriteString(after)
+		lastIndex = match[1]
+	}
+
+	highlighted.WriteString(html.EscapeString(text[lastIndex:]))
+
+	return highlighted.String()
+}
+
 /**
  *	Generates a random string at desired length.
  *
@@ -34,10 +133,33 @@ func Pick(expectedString string, defaultString string) string {
  *	@return string
  */
 func RandomString(outputLength int) string {
+	return RandomStringFrom("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", outputLength)
+}
+
+/**
+ *	Generates a random uppercase code at desired length, ambiguous characters (0, O, 1, I) are left out.
+ *	@NOTE Used for codes that are read out loud or typed by hand.
+ *
+ *	@param outputLength int - Output string length
+ *
+ *	@return string
+ */
+func RandomCode(outputLength int) string {
+	return RandomStringFrom("ABCDEFGHJKLMNPQRSTUVWXYZ23456789", outputLength)
+}
+
+/**
+ *	Generates a random string at desired length using only available characters.
+ *
+ *	@param availableCharBytes string - Characters to pick from.
+ *	@param outputLength int - Output string length
+ *
+ *	@return string
+ */
+func RandomStringFrom(availableCharBytes string, outputLength int) string {
 	var bitLength byte
 	var bitMask byte
 
-	availableCharBytes := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	availableCharLength := len(availableCharBytes)
 
 	for bits := availableCharLength - 1; bits != 0; {
@@ -116,3 +238,15 @@ func PasswordMatch(hashedPassword string, rawPassword string) bool {
 	}
 	return true
 }
+
+/**
+ *	Returns hex encoded SHA-256 hash of a token, used to store tokens that must be looked up but not recovered.
+ *
+ *	@param rawToken string - Unhashed token.
+ *
+ *	@return string
+ */
+func HashToken(rawToken string) string {
+	tokenHash := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(tokenHash[:])
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	// Native packages
+	"reflect"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	cases := map[string][]string{
+		"Jag har +aldrig, jag":      {"jag", "har", "aldrig"},
+		"<script>alert(1)</script>": {"script", "alert", "1"},
+		"ALDRIG Åkt":                {"aldrig", "åkt"},
+		" +-\"* ":                   nil,
+	}
+
+	for searchQuery, expected := range cases {
+		if searchTerms := SearchTerms(searchQuery); !reflect.DeepEqual(searchTerms, expected) {
+			t.Errorf("SearchTerms(%q) returned %q, expected %q", searchQuery, searchTerms, expected)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		text        string
+		searchTerms []string
+		expected    string
+	}{
+		{"Jag har aldrig", []string{"aldrig"}, "Jag har <mark>aldrig</mark>"},
+		{"<script>alert(1)</script>", []string{"alert"}, "&lt;script&gt;<mark>alert</mark>(1)&lt;/script&gt;"},
+		{"<script>alert(1)</script>", []string{"script"}, "&lt;<mark>script</mark>&gt;alert(1)&lt;/<mark>script</mark>&gt;"},
+		{"<script>alert(1)</script>", nil, "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"Tom & Jerry", []string{"amp"}, "Tom &amp; Jerry"},
+		{"a < b", []string{"lt"}, "a &lt; b"},
+		{"Jag har ALDRIG Aldrig", []string{"aldrig"}, "Jag har <mark>ALDRIG</mark> <mark>Aldrig</mark>"},
+		{"aldrig al", []string{"al", "aldrig"}, "<mark>aldrig</mark> <mark>al</mark>"},
+		{"aldrig", []string{"ald", "drig"}, "<mark>ald</mark>rig"},
+		{"a.b ab", []string{"a.b"}, "<mark>a.b</mark> ab"},
+	}
+
+	for _, testCase := range cases {
+		if highlighted := Highlight(testCase.text, testCase.searchTerms, "<mark>", "</mark>"); highlighted != testCase.expected {
+			t.Errorf("Highlight(%q, %q) returned %q, expected %q", testCase.text, testCase.searchTerms, highlighted, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package websocket
+
+import (
+	// Native packages
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const OPCODE_CONTINUATION = 0x0
+const OPCODE_TEXT = 0x1
+const OPCODE_BINARY = 0x2
+const OPCODE_CLOSE = 0x8
+const OPCODE_PING = 0x9
+const OPCODE_PONG = 0xA
+
+const CLOSE_NORMAL = 1000
+const CLOSE_PROTOCOL_ERROR = 1002
+const CLOSE_MESSAGE_TOO_BIG = 1009
+
+const MAX_MESSAGE_SIZE = 64 * 1024
+
+// @NOTE Magic value from RFC 6455, section 1.3.
+const HANDSHAKE_GUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var ErrBadHandshake = errors.New("Bad WebSocket handshake.")
+var ErrBadOrigin = errors.New("WebSocket origin not allowed.")
+var ErrProtocol = errors.New("WebSocket protocol error.")
+var ErrMessageTooBig = errors.New("WebSocket message too big.")
+var ErrClosed = errors.New("WebSocket connection closed.")
+
+type Conn struct {
+	ReadTimeout time.Duration
+	connection  net.Conn
+	reader      *bufio.Reader
+	writeMutex  sync.Mutex
+	closeOnce   sync.Once
+}
+
+/**
+ *	Returns true if comma separated header contains token, case insensitive.
+ *
+ *	@param header http.Header
+ *	@param name string - Header name.
+ *	@param token string - Expected token.
+ *
+ *	@return bool
+ */
+func headerContainsToken(header http.Header, name string, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+/**
+ *	Returns Sec-WebSocket-Accept value for a client handshake key.
+ *
+ *	@param handshakeKey string - Sec-WebSocket-Key header value.
+ *
+ *	@return string
+ */
+func AcceptKey(handshakeKey string) string {
+	hash := sha1.New()
+	hash.Write([]byte(handshakeKey + HANDSHAKE_GUID))
+
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
+
+/**
+ *	Returns true if request asks for a WebSocket upgrade.
+ *
+ *	@param request *http.Request
+ *
+ *	@return bool
+ */
+func IsUpgradeRequest(request *http.Request) bool {
+	return headerContainsToken(request.Header, "Connection", "upgrade") && headerContainsToken(request.Header, "Upgrade", "websocket")
+}
+
+/**
+ *	Returns true if request comes from the same host or one of allowed origins.
+ *	@NOTE Browsers always send Origin, requests without it come from other clients and cannot be hijacked cross-site.
+ *
+ *	@param request *http.Request
+ *	@param allowedOrigins []string - Allowed origins besides the request host, e.g. "https://app.example.com".
+ *
+ *	@return bool
+ */
+func IsAllowedOrigin(request *http.Request, allowedOrigins []string) bool {
+	origin := request.Header.Get("Origin")
+
+	if origin == "" {
+		return true
+	}
+
+	if originUrl, parseError := url.Parse(origin); parseError == nil && originUrl.Host != "" && strings.EqualFold(originUrl.Host, request.Host) {
+		return true
+	}
+
+	for _, allowedOrigin := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowedOrigin, "/"), origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/**
+ *	Upgrades HTTP request to a WebSocket connection using the RFC 6455 handshake.
+ *	@NOTE Nothing may be written to writer before calling Upgrade, nothing is written when the handshake is refused.
+ *
+ *	@param writer http.ResponseWriter
+ *	@param request *http.Request
+ *	@param allowedOrigins []string - Allowed origins besides the request host, see IsAllowedOrigin.
+ *
+ *	@return *Conn, error
+ */
+func Upgrade(writer http.ResponseWriter, request *http.Request, allowedOrigins []string) (*Conn, error) {
+	handshakeKey := request.Header.Get("Sec-WebSocket-Key")
+
+	if request.Method != "GET" || !IsUpgradeRequest(request) || handshakeKey == "" {
+		return nil, ErrBadHandshake
+	}
+
+	if request.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, ErrBadHandshake
+	}
+
+	if !IsAllowedOrigin(request, allowedOrigins) {
+		return nil, ErrBadOrigin
+	}
+
+	hijacker, canHijack := writer.(http.Hijacker)
+
+	if !canHijack {
+		return nil, ErrBadHandshake
+	}
+
+	connection, buffer, hijackError := hijacker.Hijack()
+
+	if hijackError != nil {
+		return nil, hijackError
+	}
+
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(handshakeKey) + "\r\n\r\n"
+
+	if _, writeError := connection.Write([]byte(handshake)); writeError != nil {
+		connection.Close()
+		return nil, writeError
+	}
+
+	return &Conn{
+		connection: connection,
+		reader:     buffer.Reader,
+	}, nil
+}
+
+/**
+ *	Reads a single frame, client frames must be masked.
+ *
+ *	@return bool, int, []byte, error - Final fragment flag, opcode, payload and error.
+ */
+func (conn *Conn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	var maskKey [4]byte
+	var payloadLength uint64
+
+	if conn.ReadTimeout > 0 {
+		conn.connection.SetReadDeadline(time.Now().Add(conn.ReadTimeout))
+	}
+
+	if _, readError := io.ReadFull(conn.reader, header[:]); readError != nil {
+		return false, 0, nil, readError
+	}
+
+	isFinal := header[0]&0x80 != 0
+	opcode := int(header[0] & 0x0F)
+	isMasked := header[1]&0x80 != 0
+	payloadLength = uint64(header[1] & 0x7F)
+
+	if header[0]&0x70 != 0 || !isMasked {
+		return false, 0, nil, ErrProtocol
+	}
+
+	switch payloadLength {
+	case 126:
+		var extendedLength [2]byte
+		if _, readError := io.ReadFull(conn.reader, extendedLength[:]); readError != nil {
+			return false, 0, nil, readError
+		}
+		payloadLength = uint64(binary.BigEndian.Uint16(extendedLength[:]))
+		break
+	case 127:
+		var extendedLength [8]byte
+		if _, readError := io.ReadFull(conn.reader, extendedLength[:]); readError != nil {
+			return false, 0, nil, readError
+		}
+		payloadLength = binary.BigEndian.Uint64(extendedLength[:])
+		break
+	}
+
+	if opcode >= OPCODE_CLOSE && (!isFinal || payloadLength > 125) {
+		return false, 0, nil, ErrProtocol
+	}
+
+	if payloadLength > MAX_MESSAGE_SIZE {
+		return false, 0, nil, ErrMessageTooBig
+	}
+
+	if _, readError := io.ReadFull(conn.reader, maskKey[:]); readError != nil {
+		return false, 0, nil, readError
+	}
+
+	payload := make([]byte, payloadLength)
+
+	if _, readError := io.ReadFull(conn.reader, payload); readError != nil {
+		return false, 0, nil, readError
+	}
+
+	for index := range payload {
+		payload[index] ^= maskKey[index%4]
+	}
+
+	return isFinal, opcode, payload, nil
+}
+
+/**
+ *	Reads next text or binary message, fragments are joined and control frames are answered.
+ *	@NOTE Returns ErrClosed once the client has sent a close frame.
+ *
+ *	@return int, []byte, error - Opcode, payload and error.
+ */
+func (conn *Conn) ReadMessage() (int, []byte, error) {
+	var messageOpcode int
+	var message []byte
+
+	for {
+		isFinal, opcode, payload, readError := conn.readFrame()
+
+		if readError != nil {
+			if readError == ErrProtocol {
+				conn.CloseWithStatus(CLOSE_PROTOCOL_ERROR)
+			} else if readError == ErrMessageTooBig {
+				conn.CloseWithStatus(CLOSE_MESSAGE_TOO_BIG)
+			}
+
+			return 0, nil, readError
+		}
+
+		switch opcode {
+		case OPCODE_PING:
+			conn.WriteMessage(OPCODE_PONG, payload)
+			continue
+		case OPCODE_PONG:
+			continue
+		case OPCODE_CLOSE:
+			conn.CloseWithStatus(CLOSE_NORMAL)
+			return 0, nil, ErrClosed
+		case OPCODE_CONTINUATION:
+			if messageOpcode == 0 {
+				conn.CloseWithStatus(CLOSE_PROTOCOL_ERROR)
+				return 0, nil, ErrProtocol
+			}
+			break
+		default:
+			if messageOpcode != 0 {
+				conn.CloseWithStatus(CLOSE_PROTOCOL_ERROR)
+				return 0, nil, ErrProtocol
+			}
+			messageOpcode = opcode
+			break
+		}
+
+		if len(message)+len(payload) > MAX_MESSAGE_SIZE {
+			conn.CloseWithStatus(CLOSE_MESSAGE_TOO_BIG)
+			return 0, nil, ErrMessageTooBig
+		}
+
+		message = append(message, payload...)
+
+		if isFinal {
+			return messageOpcode, message, nil
+		}
+	}
+}
+
+/**
+ *	Writes a single unmasked frame, safe for concurrent writers.
+ *
+ *	@param opcode int
+ *	@param payload []byte
+ *
+ *	@return error
+ */
+func (conn *Conn) WriteMessage(opcode int, payload []byte) error {
+	var frame []byte
+
+	payloadLength := len(payload)
+	frame = append(frame, byte(0x80|opcode))
+
+	switch {
+	case payloadLength <= 125:
+		frame = append(frame, byte(payloadLength))
+		break
+	case payloadLength <= 0xFFFF:
+		frame = append(frame, 126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(payloadLength))
+		break
+	default:
+		frame = append(frame, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(payloadLength))
+		break
+	}
+
+	frame = append(frame, payload...)
+
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+
+	_, writeError := conn.connection.Write(frame)
+
+	return writeError
+}
+
+/**
+ *	Encodes value as JSON and writes it as a text message.
+ *
+ *	@param value interface{}
+ *
+ *	@return error
+ */
+func (conn *Conn) WriteJSON(value interface{}) error {
+	message, encodeError := json.Marshal(value)
+
+	if encodeError != nil {
+		return encodeError
+	}
+
+	return conn.WriteMessage(OPCODE_TEXT, message)
+}
+
+/**
+ *	Sends close frame with status code and closes underlying connection, safe to call more than once.
+ *
+ *	@param statusCode int
+ *
+ *	@return error
+ */
+func (conn *Conn) CloseWithStatus(statusCode int) error {
+	var closeError error
+
+	conn.closeOnce.Do(func() {
+		closePayload := make([]byte, 2)
+		binary.BigEndian.PutUint16(closePayload, uint16(statusCode))
+
+		conn.WriteMessage(OPCODE_CLOSE, closePayload)
+		closeError = conn.connection.Close()
+	})
+
+	return closeError
+}
+
+/**
+ *	Closes connection with CLOSE_NORMAL status.
+ *
+ *	@return error
+ */
+func (conn *Conn) Close() error {
+	return conn.CloseWithStatus(CLOSE_NORMAL)
+}
@@ -0,0 +1,375 @@
+package websocket
+
+import (
+	// Native packages
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testMaskKey = []byte{0x37, 0xfa, 0x21, 0x3d}
+
+/**
+ *	Returns server side connection and client end of an in-memory pipe.
+ */
+func newTestConn() (*Conn, net.Conn) {
+	server, client := net.Pipe()
+
+	return &Conn{connection: server, reader: bufio.NewReader(server)}, client
+}
+
+/**
+ *	Encodes a client frame, masked with testMaskKey unless isMasked is false.
+ */
+func clientFrame(isFinal bool, opcode int, payload []byte, isMasked bool) []byte {
+	var frame []byte
+	var maskBit byte
+
+	firstByte := byte(opcode)
+
+	if isFinal {
+		firstByte |= 0x80
+	}
+
+	if isMasked {
+		maskBit = 0x80
+	}
+
+	frame = append(frame, firstByte)
+
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, maskBit|byte(len(payload)))
+		break
+	case len(payload) <= 0xFFFF:
+		frame = append(frame, maskBit|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+		break
+	default:
+		frame = append(frame, maskBit|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(len(payload)))
+		break
+	}
+
+	if !isMasked {
+		return append(frame, payload...)
+	}
+
+	frame = append(frame, testMaskKey...)
+
+	for index, payloadByte := range payload {
+		frame = append(frame, payloadByte^testMaskKey[index%4])
+	}
+
+	return frame
+}
+
+/**
+ *	Writes frames from client in the background, net.Pipe blocks until server reads them.
+ */
+func writeFrames(client net.Conn, frames ...[]byte) {
+	go func() {
+		for _, frame := range frames {
+			if _, writeError := client.Write(frame); writeError != nil {
+				return
+			}
+		}
+	}()
+}
+
+/**
+ *	Collects everything server sends until it closes the connection.
+ */
+func readAll(client net.Conn) chan []byte {
+	received := make(chan []byte, 1)
+
+	go func() {
+		data, _ := ioutil.ReadAll(client)
+		received <- data
+	}()
+
+	return received
+}
+
+/**
+ *	Returns unmasked close frame with status code, as sent by server.
+ */
+func closeFrame(statusCode int) []byte {
+	frame := []byte{0x80 | OPCODE_CLOSE, 2, 0, 0}
+	binary.BigEndian.PutUint16(frame[2:], uint16(statusCode))
+
+	return frame
+}
+
+func TestAcceptKey(t *testing.T) {
+	// @NOTE Sample handshake from RFC 6455, section 1.3.
+	if acceptKey := AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); acceptKey != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("AcceptKey returned %q", acceptKey)
+	}
+}
+
+func TestReadMaskedFrame(t *testing.T) {
+	conn, client := newTestConn()
+	defer client.Close()
+	defer conn.connection.Close()
+
+	// @NOTE Masked "Hello" text frame from RFC 6455, section 5.7.
+	writeFrames(client, []byte{0x81, 0x85, 0x37, 0xfa, 0x21, 0x3d, 0x7f, 0x9f, 0x4d, 0x51, 0x58})
+
+	opcode, message, readError := conn.ReadMessage()
+
+	if readError != nil || opcode != OPCODE_TEXT || string(message) != "Hello" {
+		t.Errorf("ReadMessage returned %d %q %v", opcode, message, readError)
+	}
+}
+
+func TestReadExtendedLengthFrame(t *testing.T) {
+	conn, client := newTestConn()
+	defer client.Close()
+	defer conn.connection.Close()
+
+	payload := bytes.Repeat([]byte("a"), 300)
+	writeFrames(client, clientFrame(true, OPCODE_BINARY, payload, true))
+
+	opcode, message, readError := conn.ReadMessage()
+
+	if readError != nil || opcode != OPCODE_BINARY || !bytes.Equal(message, payload) {
+		t.Errorf("ReadMessage returned %d, %d bytes, %v", opcode, len(message), readError)
+	}
+}
+
+func TestRejectsUnmaskedFrame(t *testing.T) {
+	conn, client := newTestConn()
+	defer client.Close()
+
+	writeFrames(client, clientFrame(true, OPCODE_TEXT, []byte("Hello"), false))
+	received := readAll(client)
+
+	if _, _, readError := conn.ReadMessage(); readError != ErrProtocol {
+		t.Errorf("ReadMessage returned %v, expected protocol error", readError)
+	}
+
+	if data := <-received; !bytes.Equal(data, closeFrame(CLOSE_PROTOCOL_ERROR)) {
+		t.Errorf("Server sent % x, expected close frame with status %d", data, CLOSE_PROTOCOL_ERROR)
+	}
+}
+
+func TestReadFragmentedMessage(t *testing.T) {
+	conn, client := newTestConn()
+	defer client.Close()
+
+	writeFrames(client,
+		clientFrame(false, OPCODE_TEXT, []byte("Hel"), true),
+		clientFrame(true, OPCODE_PING, []byte("hb"), true),
+		clientFrame(true, OPCODE_CONTINUATION, []byte("lo"), true),
+	)
+	received := readAll(client)
+
+	opcode, message, readError := conn.ReadMessage()
+
+	if readError != nil || opcode != OPCODE_TEXT || string(message) != "Hello" {
+		t.Errorf("ReadMessage returned %d %q %v", opcode, message, readError)
+	}
+
+	conn.Close()
+
+	expected := append([]byte{0x80 | OPCODE_PONG, 2, 'h', 'b'}, closeFrame(CLOSE_NORMAL)...)
+
+	if data := <-received; !bytes.Equal(data, expected) {
+		t.Errorf("Server sent % x, expected pong and close frames % x", data, expected)
+	}
+}
+
+func TestRejectsUnexpectedContinuation(t *testing.T) {
+	conn, client := newTestConn()
+	defer client.Close()
+
+	writeFrames(client, clientFrame(true, OPCODE_CONTINUATION, []byte("lo"), true))
+	received := readAll(client)
+
+	if _, _, readError := conn.ReadMessage(); readError != ErrProtocol {
+		t.Errorf("ReadMessage returned %v, expected protocol error", readError)
+	}
+
+	if data := <-received; !bytes.Equal(data, closeFrame(CLOSE_PROTOCOL_ERROR)) {
+		t.Errorf("Server sent % x, expected close frame with status %d", data, CLOSE_PROTOCOL_ERROR)
+	}
+}
+
+func TestRejectsOversizedFrame(t *testing.T) {
+	conn, client := newTestConn()
+	defer client.Close()
+
+	// @NOTE Only the header is sent, length is checked before the payload is read.
+	header := []byte{0x80 | OPCODE_BINARY, 0x80 | 127, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint64(header[2:], MAX_MESSAGE_SIZE+1)
+
+	writeFrames(client, header)
+	received := readAll(client)
+
+	if _, _, readError := conn.ReadMessage(); readError != ErrMessageTooBig {
+		t.Errorf("ReadMessage returned %v, expected message too big", readError)
+	}
+
+	if data := <-received; !bytes.Equal(data, closeFrame(CLOSE_MESSAGE_TOO_BIG)) {
+		t.Errorf("Server sent % x, expected close frame with status %d", data, CLOSE_MESSAGE_TOO_BIG)
+	}
+}
+
+func TestRejectsOversizedFragments(t *testing.T) {
+	conn, client := newTestConn()
+	defer client.Close()
+
+	fragment := bytes.Repeat([]byte("a"), MAX_MESSAGE_SIZE/2+1)
+
+	writeFrames(client,
+		clientFrame(false, OPCODE_TEXT, fragment, true),
+		clientFrame(true, OPCODE_CONTINUATION, fragment, true),
+	)
+	received := readAll(client)
+
+	if _, _, readError := conn.ReadMessage(); readError != ErrMessageTooBig {
+		t.Errorf("ReadMessage returned %v, expected message too big", readError)
+	}
+
+	if data := <-received; !bytes.Equal(data, closeFrame(CLOSE_MESSAGE_TOO_BIG)) {
+		t.Errorf("Server sent % x, expected close frame with status %d", data, CLOSE_MESSAGE_TOO_BIG)
+	}
+}
+
+func TestCloseHandshake(t *testing.T) {
+	conn, client := newTestConn()
+	defer client.Close()
+
+	writeFrames(client, clientFrame(true, OPCODE_CLOSE, []byte{0x03, 0xe8}, true))
+	received := readAll(client)
+
+	if _, _, readError := conn.ReadMessage(); readError != ErrClosed {
+		t.Errorf("ReadMessage returned %v, expected closed connection", readError)
+	}
+
+	if data := <-received; !bytes.Equal(data, closeFrame(CLOSE_NORMAL)) {
+		t.Errorf("Server sent % x, expected close frame with status %d", data, CLOSE_NORMAL)
+	}
+
+	if closeError := conn.Close(); closeError != nil {
+		t.Errorf("Second Close returned %v", closeError)
+	}
+}
+
+func TestWriteMessageIsUnmasked(t *testing.T) {
+	cases := map[int][]byte{
+		5:     {0x81, 5},
+		200:   {0x81, 126, 0x00, 0xc8},
+		70000: {0x81, 127, 0, 0, 0, 0, 0, 0x01, 0x11, 0x70},
+	}
+
+	for payloadLength, expectedHeader := range cases {
+		conn, client := newTestConn()
+		received := readAll(client)
+
+		payload := bytes.Repeat([]byte("a"), payloadLength)
+
+		if writeError := conn.WriteMessage(OPCODE_TEXT, payload); writeError != nil {
+			t.Errorf("WriteMessage of %d bytes failed: %s", payloadLength, writeError)
+		}
+
+		conn.connection.Close()
+		data := <-received
+		client.Close()
+
+		if !bytes.Equal(data, append(expectedHeader, payload...)) {
+			t.Errorf("WriteMessage of %d bytes sent header % x, expected % x", payloadLength, data[:len(expectedHeader)], expectedHeader)
+		}
+	}
+}
+
+func TestIsAllowedOrigin(t *testing.T) {
+	allowedOrigins := []string{"https://app.example.com/"}
+
+	cases := map[string]bool{
+		"":                         true,
+		"https://api.example.com":  true,
+		"http://API.example.com":   true,
+		"https://app.example.com":  true,
+		"https://evil.example.com": false,
+		"null":                     false,
+	}
+
+	for origin, expected := range cases {
+		request := httptest.NewRequest("GET", "http://api.example.com/v1/rooms/abcdef/socket", nil)
+
+		if origin != "" {
+			request.Header.Set("Origin", origin)
+		}
+
+		if allowed := IsAllowedOrigin(request, allowedOrigins); allowed != expected {
+			t.Errorf("Origin %q allowed %t, expected %t", origin, allowed, expected)
+		}
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		conn, upgradeError := Upgrade(writer, request, nil)
+
+		if upgradeError == ErrBadOrigin {
+			writer.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if upgradeError != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		conn.Close()
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	cases := map[string]string{
+		"":                         "HTTP/1.1 101 Switching Protocols",
+		"http://" + host:           "HTTP/1.1 101 Switching Protocols",
+		"https://evil.example.com": "HTTP/1.1 403 Forbidden",
+	}
+
+	for origin, expectedStatus := range cases {
+		connection, dialError := net.Dial("tcp", host)
+
+		if dialError != nil {
+			t.Fatalf("Dial failed: %s", dialError)
+		}
+
+		handshake := "GET / HTTP/1.1\r\nHost: " + host + "\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n" +
+			"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"
+
+		if origin != "" {
+			handshake += "Origin: " + origin + "\r\n"
+		}
+
+		connection.Write([]byte(handshake + "\r\n"))
+
+		response, readError := http.ReadResponse(bufio.NewReader(connection), nil)
+		connection.Close()
+
+		if readError != nil {
+			t.Fatalf("Reading handshake response failed: %s", readError)
+		}
+
+		if status := response.Proto + " " + response.Status; status != expectedStatus {
+			t.Errorf("Origin %q got %q, expected %q", origin, status, expectedStatus)
+		}
+
+		if response.StatusCode == http.StatusSwitchingProtocols && response.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+			t.Errorf("Handshake sent accept key %q", response.Header.Get("Sec-WebSocket-Accept"))
+		}
+	}
+}